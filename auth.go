@@ -0,0 +1,223 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+// tokenExpiryMargin represents the margin before expiry at which an access token is refreshed.
+const tokenExpiryMargin = 10 * time.Second
+
+// serviceAccount represents the Keycloak service account used to authenticate API calls.
+type serviceAccount struct {
+	keycloakEndpoint string
+	clientID         string
+	clientSecret     string
+
+	mutex    sync.Mutex
+	token    string
+	expiry   time.Time
+	disabled bool
+}
+
+// staticCredentials represents static credentials sent with every API call, e.g. expected by a gateway in front of
+// a remote Microcks instance.
+type staticCredentials struct {
+	bearerToken  string
+	apiKeyHeader string
+	apiKey       string
+}
+
+// WithBearerToken sends a static bearer token with every call to the Microcks API, e.g. when a remote instance sits
+// behind a gateway. When a service account is set, its access token is sent instead, as long as Keycloak is enabled.
+func WithBearerToken(token string) Option {
+	return func(o *options) error {
+		if token == "" {
+			return fmt.Errorf("error setting bearer token: token is empty")
+		}
+		o.credentials.bearerToken = token
+		return nil
+	}
+}
+
+// WithAPIKey sends an API key in given header, e.g. "X-API-Key", with every call to the Microcks API, e.g. when a
+// remote instance sits behind a gateway. It can be combined with a service account.
+func WithAPIKey(header, key string) Option {
+	return func(o *options) error {
+		if header == "" || key == "" {
+			return fmt.Errorf("error setting API key: header and key must not be empty")
+		}
+		o.credentials.apiKeyHeader = header
+		o.credentials.apiKey = key
+		return nil
+	}
+}
+
+// credentialsTransport represents a transport adding static credentials to requests.
+type credentialsTransport struct {
+	next        http.RoundTripper
+	credentials staticCredentials
+}
+
+func (t *credentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.credentials.bearerToken != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+t.credentials.bearerToken)
+	}
+	if t.credentials.apiKey != "" {
+		req.Header.Set(t.credentials.apiKeyHeader, t.credentials.apiKey)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// authorize adds the service account access token to an API request, if any.
+// It is compatible with client.RequestEditorFn.
+func (container *MicrocksContainer) authorize(ctx context.Context, req *http.Request) error {
+	if container.serviceAccount == nil {
+		return nil
+	}
+
+	token, err := container.serviceAccount.accessToken(ctx, container)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return nil
+}
+
+// APIClient returns a client of the Microcks API, authenticated with the service account, if any, e.g. to call APIs
+// not covered by the container helpers.
+func (container *MicrocksContainer) APIClient(ctx context.Context) (*apiclient.Client, error) {
+	httpEndpoint, err := container.HttpEndpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving Microcks API endpoint: %w", err)
+	}
+
+	return apiclient.New(httpEndpoint,
+		apiclient.WithHTTPClient(container.apiHTTPClient()),
+		apiclient.WithRequestEditor(container.authorize),
+		apiclient.WithServiceCache(container.serviceCache),
+	)
+}
+
+// InvalidateServiceCache empties the cache of the services and operations looked up by name and version, e.g. by
+// SetDispatcher or Messages. Imports and updates made through the module invalidate it: call it after changing
+// Microcks content otherwise, e.g. from its UI or another client.
+func (container *MicrocksContainer) InvalidateServiceCache() {
+	if container.serviceCache != nil {
+		container.serviceCache.Invalidate()
+	}
+}
+
+// createdStatusCode converts the result of a client call expecting 201 to a status code, as returned by import
+// methods: errors other than an unexpected status code are returned.
+func createdStatusCode(err error) (int, error) {
+	var statusErr *apiclient.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusCreated, nil
+}
+
+// accessToken gets a valid access token, requesting a new one when the current one is about to expire.
+func (sa *serviceAccount) accessToken(ctx context.Context, container *MicrocksContainer) (string, error) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	if sa.disabled {
+		return "", nil
+	}
+	if sa.token != "" && time.Now().Before(sa.expiry) {
+		return sa.token, nil
+	}
+
+	// Retrieve Keycloak configuration from Microcks. Authentication being disabled is remembered, as it cannot be
+	// enabled without restarting Microcks.
+	config, err := container.keycloakConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !config.Enabled {
+		sa.disabled = true
+		return "", nil
+	}
+
+	// Request a new token using client credentials.
+	container.logf("Requesting access token for service account %s", sa.clientID)
+	tokenURL := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", sa.keycloakEndpoint, url.PathEscape(config.Realm))
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(sa.clientID, sa.clientSecret)
+
+	response, err := container.newHTTPClient(staticCredentials{}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error getting service account token: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", apiclient.NewStatusError("get service account token", response.StatusCode, http.StatusOK, nil)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding service account token: %w", err)
+	}
+
+	sa.token = token.AccessToken
+	sa.expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+
+	return sa.token, nil
+}
+
+func (container *MicrocksContainer) keycloakConfig(ctx context.Context) (*apiclient.KeycloakConfig, error) {
+	httpEndpoint, err := container.HttpEndpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving Microcks API endpoint: %w", err)
+	}
+
+	// Keycloak configuration is public, and needed to authenticate other calls.
+	c, err := apiclient.New(httpEndpoint, apiclient.WithHTTPClient(container.apiHTTPClient()))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.KeycloakConfig(ctx)
+}
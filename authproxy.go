@@ -0,0 +1,143 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// AuthRequirement represents a credential required to call the mock operations matching Method and PathPrefix.
+// Requests without credential are rejected with 401, requests with a wrong one with 403.
+type AuthRequirement struct {
+	// Method is the HTTP method of matching operations, empty to match any method.
+	Method string
+	// PathPrefix is the prefix of matching mock paths, e.g. "/rest/API Pastries/0.0.1/pastries".
+	PathPrefix string
+	// APIKeyHeader is the name of the header holding the API key, used when APIKey is set.
+	APIKeyHeader string
+	// APIKey is the expected API key.
+	APIKey string
+	// BearerToken is the expected bearer token of the Authorization header.
+	BearerToken string
+}
+
+// AuthProxy represents a local HTTP proxy in front of Microcks mocks, enforcing authentication requirements.
+type AuthProxy struct {
+	server   *http.Server
+	listener net.Listener
+	target   *url.URL
+}
+
+// StartAuthProxy starts a local HTTP proxy in front of Microcks mocks that enforces given authentication
+// requirements, so that negative paths of the application under test can be exercised.
+// Requests matching no requirement are forwarded as is.
+func (container *MicrocksContainer) StartAuthProxy(ctx context.Context, requirements ...AuthRequirement) (*AuthProxy, error) {
+	endpoint, err := container.HttpEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Microcks endpoint: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("error starting authentication proxy: %w", err)
+	}
+
+	reverseProxy := httputil.NewSingleHostReverseProxy(target)
+	reverseProxy.Transport = container.transport()
+
+	proxy := &AuthProxy{
+		server:   &http.Server{Handler: authHandler(reverseProxy, requirements)},
+		listener: listener,
+		target:   target,
+	}
+	go func() {
+		_ = proxy.server.Serve(listener)
+	}()
+
+	return proxy, nil
+}
+
+// URL returns the base URL of the proxy.
+func (proxy *AuthProxy) URL() string {
+	return "http://" + proxy.listener.Addr().String()
+}
+
+// Endpoint rewrites a Microcks mock endpoint, as returned by RestMockEndpoint or others, so that it goes
+// through the proxy.
+func (proxy *AuthProxy) Endpoint(mockEndpoint string) string {
+	return strings.Replace(mockEndpoint, proxy.target.Scheme+"://"+proxy.target.Host, proxy.URL(), 1)
+}
+
+// Close stops the proxy.
+func (proxy *AuthProxy) Close(ctx context.Context) error {
+	return proxy.server.Shutdown(ctx)
+}
+
+// authHandler wraps next handler, rejecting requests not fulfilling matching requirements.
+func authHandler(next http.Handler, requirements []AuthRequirement) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, requirement := range requirements {
+			if !requirement.matches(r) {
+				continue
+			}
+			if status := requirement.check(r); status != http.StatusOK {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (requirement AuthRequirement) matches(r *http.Request) bool {
+	if requirement.Method != "" && !strings.EqualFold(requirement.Method, r.Method) {
+		return false
+	}
+	return strings.HasPrefix(r.URL.Path, requirement.PathPrefix)
+}
+
+// check returns the status code resulting of requirement verification on request.
+func (requirement AuthRequirement) check(r *http.Request) int {
+	if requirement.APIKey != "" {
+		key := r.Header.Get(requirement.APIKeyHeader)
+		if key == "" {
+			return http.StatusUnauthorized
+		}
+		if key != requirement.APIKey {
+			return http.StatusForbidden
+		}
+	}
+	if requirement.BearerToken != "" {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || token == "" {
+			return http.StatusUnauthorized
+		}
+		if token != requirement.BearerToken {
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusOK
+}
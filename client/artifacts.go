@@ -0,0 +1,186 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// UploadArtifact imports the artifact read from r, named fileName, e.g. an OpenAPI or Postman collection file.
+// Secondary artifacts complete the main artifact of a Service, e.g. with examples. The artifact is streamed, not
+// loaded in memory: the request can only be retried when r is an io.Seeker, e.g. an *os.File.
+func (c *Client) UploadArtifact(ctx context.Context, fileName string, r io.Reader, mainArtifact bool) error {
+	action := "import artifact " + fileName
+	req, err := c.newMultipartRequest(ctx, "/api/artifact/upload", fileName, r, map[string]string{"mainArtifact": strconv.FormatBool(mainArtifact)})
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", action, err)
+	}
+
+	return c.upload(action, req)
+}
+
+// DownloadArtifact makes Microcks import the artifact it downloads from given URL, using the secret having given
+// name, if not empty, to authenticate.
+func (c *Client) DownloadArtifact(ctx context.Context, artifactURL string, mainArtifact bool, secretName string) error {
+	form := url.Values{
+		"url":          {artifactURL},
+		"mainArtifact": {strconv.FormatBool(mainArtifact)},
+	}
+	if secretName != "" {
+		form.Set("secretName", secretName)
+	}
+
+	action := "import remote artifact " + artifactURL
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/artifact/download", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.upload(action, req)
+}
+
+// ImportSnapshot imports the snapshot read from r, named fileName, as exported by ExportSnapshot. The snapshot is
+// streamed as UploadArtifact does.
+func (c *Client) ImportSnapshot(ctx context.Context, fileName string, r io.Reader) error {
+	action := "import snapshot " + fileName
+	req, err := c.newMultipartRequest(ctx, "/api/import", fileName, r, nil)
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", action, err)
+	}
+
+	return c.upload(action, req)
+}
+
+// ExportSnapshot writes a snapshot of the Services having given identifiers to w.
+func (c *Client) ExportSnapshot(ctx context.Context, w io.Writer, serviceIDs ...string) error {
+	query := url.Values{}
+	for _, id := range serviceIDs {
+		query.Add("serviceIds", id)
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/export?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating export request: %w", err)
+	}
+
+	response, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("error exporting snapshot: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return NewStatusError("export snapshot", response.StatusCode, http.StatusOK, nil)
+	}
+	if _, err := io.Copy(w, response.Body); err != nil {
+		return fmt.Errorf("error reading snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// upload sends an import request, expecting 201, and invalidates the Service cache.
+func (c *Client) upload(action string, req *http.Request) error {
+	// Imports may create or replace any service, even when they fail midway.
+	defer c.invalidateServices()
+
+	response, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending %s request: %w", action, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		return NewStatusError(action, response.StatusCode, http.StatusCreated, ErrArtifactRejected)
+	}
+	return nil
+}
+
+// newMultipartRequest creates a POST request of given API path whose multipart form body holds given fields and
+// the file read from r. The file is streamed between the part headers and the closing boundary, generated upfront,
+// so that the content length is known when the size of r is. The body can be read again when r is an io.Seeker.
+func (c *Client) newMultipartRequest(ctx context.Context, path, fileName string, r io.Reader, fields map[string]string) (*http.Request, error) {
+	buffer := &bytes.Buffer{}
+	writer := multipart.NewWriter(buffer)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := writer.CreateFormFile("file", fileName); err != nil {
+		return nil, err
+	}
+	prefix := bytes.Clone(buffer.Bytes())
+	buffer.Reset()
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	suffix := buffer.Bytes()
+
+	body := func() io.Reader {
+		return io.MultiReader(bytes.NewReader(prefix), r, bytes.NewReader(suffix))
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, path, body())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	size := readerSize(r)
+	req.ContentLength = -1
+	if size >= 0 {
+		req.ContentLength = int64(len(prefix)) + size + int64(len(suffix))
+	}
+	if seeker, ok := r.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return io.NopCloser(body()), nil
+			}
+		}
+	}
+	return req, nil
+}
+
+// readerSize returns the number of bytes remaining in r, or -1 when unknown.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ServiceCache represents a cache of the Services found by name and version, with their operations, e.g. shared by
+// the Clients of a Microcks instance so that helpers called thousands of times do not look services up every time.
+// Clients invalidate it when they import artifacts, or update or delete services; Invalidate has to be called when
+// Microcks content changes otherwise.
+type ServiceCache struct {
+	mutex    sync.Mutex
+	services map[serviceKey]*Service
+}
+
+// serviceKey represents the key of a Service in a ServiceCache.
+type serviceKey struct {
+	name    string
+	version string
+}
+
+// NewServiceCache creates an empty ServiceCache.
+func NewServiceCache() *ServiceCache {
+	return &ServiceCache{services: make(map[serviceKey]*Service)}
+}
+
+// WithServiceCache sets the cache of the Services found by FindService.
+func WithServiceCache(cache *ServiceCache) Option {
+	return func(c *Client) error {
+		c.serviceCache = cache
+		return nil
+	}
+}
+
+// Invalidate empties the cache.
+func (sc *ServiceCache) Invalidate() {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	clear(sc.services)
+}
+
+// invalidateID removes the Service having given identifier from the cache.
+func (sc *ServiceCache) invalidateID(id string) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	for key, service := range sc.services {
+		if service.ID == id {
+			delete(sc.services, key)
+		}
+	}
+}
+
+// get returns a copy of the cached Service having given name and version, or nil if not cached.
+func (sc *ServiceCache) get(name, version string) *Service {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	if service, found := sc.services[serviceKey{name, version}]; found {
+		return cloneService(service)
+	}
+	return nil
+}
+
+// put caches a copy of service.
+func (sc *ServiceCache) put(service *Service) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.services[serviceKey{service.Name, service.Version}] = cloneService(service)
+}
+
+// cloneService returns a copy of service that callers can modify without altering the cached one.
+func cloneService(service *Service) *Service {
+	clone := *service
+	if service.Metadata != nil {
+		metadata := *service.Metadata
+		metadata.Labels = maps.Clone(metadata.Labels)
+		clone.Metadata = &metadata
+	}
+	clone.Operations = slices.Clone(service.Operations)
+	for i := range clone.Operations {
+		clone.Operations[i].ResourcePaths = slices.Clone(clone.Operations[i].ResourcePaths)
+	}
+	return &clone
+}
+
+// invalidateServices empties the Service cache, if any, e.g. after an import.
+func (c *Client) invalidateServices() {
+	if c.serviceCache != nil {
+		c.serviceCache.Invalidate()
+	}
+}
+
+// invalidateService removes the Service having given identifier from the Service cache, if any.
+func (c *Client) invalidateService(id string) {
+	if c.serviceCache != nil {
+		c.serviceCache.invalidateID(id)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	microcks "microcks.io/go-client"
+)
+
+// Secret represents a Microcks secret, as defined by the Microcks API.
+type Secret = microcks.Secret
+
+// TestRequest represents a request to launch a Microcks contract test, as defined by the Microcks API.
+type TestRequest = microcks.TestRequest
+
+// TestResult represents the result of a Microcks contract test, as defined by the Microcks API.
+type TestResult = microcks.TestResult
+
+// RequestEditor represents a function editing every request before it is sent, e.g. to add credentials.
+type RequestEditor func(ctx context.Context, req *http.Request) error
+
+// Client represents a client of the Microcks API, e.g. to script Microcks beyond what the container helpers cover.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	editors     []RequestEditor
+	retryPolicy RetryPolicy
+	rateLimiter RateLimiter
+
+	serviceCache *ServiceCache
+}
+
+// Option represents an option to pass to the Client.
+type Option func(*Client) error
+
+// New creates a Client of the Microcks instance reachable at given base URL, e.g. the HttpEndpoint of a
+// MicrocksContainer.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		return nil, fmt.Errorf("error creating Microcks client: invalid base URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.rateLimiter != nil || c.retryPolicy != nil {
+		httpClient := *c.httpClient
+		if c.rateLimiter != nil {
+			httpClient.Transport = NewRateLimitTransport(httpClient.Transport, c.rateLimiter)
+		}
+		if c.retryPolicy != nil {
+			httpClient.Transport = NewRetryTransport(httpClient.Transport, c.retryPolicy)
+		}
+		c.httpClient = &httpClient
+	}
+
+	return c, nil
+}
+
+// WithHTTPClient sets the HTTP client used to call Microcks, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return fmt.Errorf("error setting HTTP client: client is nil")
+		}
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithRequestEditor adds a function editing every request before it is sent, e.g. to authenticate it.
+func WithRequestEditor(editor RequestEditor) Option {
+	return func(c *Client) error {
+		c.editors = append(c.editors, editor)
+		return nil
+	}
+}
+
+// WithBearerToken authenticates every request with a static bearer token, e.g. expected by a gateway in front of
+// Microcks. It replaces the Authorization header set by previous request editors.
+func WithBearerToken(token string) Option {
+	return func(c *Client) error {
+		if token == "" {
+			return fmt.Errorf("error setting bearer token: token is empty")
+		}
+		return WithRequestEditor(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		})(c)
+	}
+}
+
+// WithAPIKey authenticates every request with an API key sent in given header, e.g. "X-API-Key", as expected by a
+// gateway in front of Microcks.
+func WithAPIKey(header, key string) Option {
+	return func(c *Client) error {
+		if header == "" || key == "" {
+			return fmt.Errorf("error setting API key: header and key must not be empty")
+		}
+		return WithRequestEditor(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set(header, key)
+			return nil
+		})(c)
+	}
+}
+
+// BaseURL returns the base URL of the Microcks instance.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Do applies request editors to req and sends it, e.g. to call an API not covered by the Client.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	for _, editor := range c.editors {
+		if err := editor(req.Context(), req); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// newRequest creates a request of given API path, relative to the base URL, e.g. "/api/services".
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+}
+
+// call sends a request of given API path, checks the response has the expected status code and decodes its JSON
+// body into out, if not nil. in, if not nil, is encoded as the JSON body of the request.
+func (c *Client) call(ctx context.Context, action, method, path string, in any, expected int, out any) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("error encoding %s request: %w", action, err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", action, err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending %s request: %w", action, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != expected {
+		return NewStatusError(action, response.StatusCode, expected, nil)
+	}
+	if out != nil {
+		if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+			return fmt.Errorf("error decoding %s response: %w", action, err)
+		}
+	}
+
+	return nil
+}
+
+// KeycloakConfig represents the Keycloak configuration exposed by Microcks.
+type KeycloakConfig struct {
+	// Enabled tells if Microcks requires authentication.
+	Enabled bool `json:"enabled"`
+
+	// Realm represents the Keycloak realm.
+	Realm string `json:"realm"`
+}
+
+// KeycloakConfig gets the Keycloak configuration of Microcks.
+func (c *Client) KeycloakConfig(ctx context.Context) (*KeycloakConfig, error) {
+	config := &KeycloakConfig{}
+	if err := c.call(ctx, "get Keycloak config", http.MethodGet, "/api/keycloak/config", nil, http.StatusOK, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Version represents the version of Microcks.
+type Version struct {
+	// VersionID represents the Microcks version, e.g. "1.9.0".
+	VersionID string `json:"versionId"`
+
+	// BuildTimestamp represents the Microcks build timestamp.
+	BuildTimestamp string `json:"buildTimestamp"`
+}
+
+// Version gets the version of Microcks.
+func (c *Client) Version(ctx context.Context) (*Version, error) {
+	version := &Version{}
+	if err := c.call(ctx, "get version", http.MethodGet, "/api/version/info", nil, http.StatusOK, version); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
@@ -0,0 +1,527 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"microcks.io/testcontainers-go/client"
+)
+
+func TestNew(t *testing.T) {
+	_, err := client.New("microcks:8080")
+	require.Error(t, err)
+
+	c, err := client.New("http://microcks:8080/")
+	require.NoError(t, err)
+	require.Equal(t, "http://microcks:8080", c.BaseURL())
+}
+
+func TestUploadArtifact(t *testing.T) {
+	var authorization, fileName, content, mainArtifact string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/artifact/upload", r.URL.Path)
+		authorization = r.Header.Get("Authorization")
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(file)
+		fileName, content, mainArtifact = header.Filename, string(body), r.FormValue("mainArtifact")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL, client.WithRequestEditor(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer token")
+		return nil
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, c.UploadArtifact(context.Background(), "openapi.yaml", strings.NewReader("openapi: 3.0.0"), true))
+	require.Equal(t, "Bearer token", authorization)
+	require.Equal(t, "openapi.yaml", fileName)
+	require.Equal(t, "openapi: 3.0.0", content)
+	require.Equal(t, "true", mainArtifact)
+}
+
+func TestStatusError(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	err = c.DownloadArtifact(context.Background(), "https://example.com/openapi.yaml", true, "")
+	var statusErr *client.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	require.Equal(t, "unable to import remote artifact https://example.com/openapi.yaml, bad status code, actual 400, expected 201", err.Error())
+}
+
+func TestListServices(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// First page is full, second one is not.
+		count := 100
+		if r.URL.Query().Get("page") == "1" {
+			count = 2
+		}
+		services := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			services = append(services, fmt.Sprintf(`{"id":"%s-%d","name":"API","version":"%d"}`, r.URL.Query().Get("page"), i, i))
+		}
+		_, _ = io.WriteString(w, "["+strings.Join(services, ",")+"]")
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	services, err := c.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, services, 102)
+	require.Equal(t, "1-1", services[101].ID)
+}
+
+func TestInvocationStats(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("day") == "20240102" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.Equal(t, "/api/metrics/invocations/API Pastries/0.0.1", r.URL.Path)
+		_, _ = io.WriteString(w, `{"dailyCount":3,"hourlyCount":{"10":3}}`)
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	stats, err := c.InvocationStats(context.Background(), "API Pastries", "0.0.1", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.DailyCount)
+	require.Equal(t, "20240101", stats.Day)
+
+	stats, err = c.InvocationStats(context.Background(), "API Pastries", "0.0.1", time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Zero(t, stats.DailyCount)
+}
+
+func TestGetServiceAndResources(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/services/1":
+			require.Equal(t, "false", r.URL.Query().Get("messages"))
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1","type":"REST","operations":[
+				{"name":"GET /pastries","method":"GET","dispatcher":"URI_PARAMS","dispatcherRules":"size","resourcePaths":["/pastries?size=S"]}]}`)
+		case "/api/resources/service/1":
+			_, _ = io.WriteString(w, `[{"id":"r1","name":"API Pastries-0.0.1.yaml","content":"openapi: 3.0.0","type":"OPEN_API_SPEC","serviceId":"1","mainArtifact":true}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	service, err := c.GetService(context.Background(), "1")
+	require.NoError(t, err)
+	require.Equal(t, client.ServiceTypeREST, service.Type)
+	operation := service.Operation("GET /pastries")
+	require.NotNil(t, operation)
+	require.Equal(t, client.DispatcherURIParams, operation.Dispatcher)
+	require.Equal(t, "size", operation.DispatcherRules)
+	require.Nil(t, service.Operation("DELETE /pastries"))
+
+	resources, err := c.ListResources(context.Background(), "1")
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, client.ResourceTypeOpenAPISpec, resources[0].Type)
+
+	_, err = c.GetService(context.Background(), "2")
+	require.Error(t, err)
+}
+
+func TestListMessages(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/services/1", r.URL.Path)
+		require.Equal(t, "true", r.URL.Query().Get("messages"))
+		_, _ = io.WriteString(w, `{"service":{"id":"1"},"messagesMap":{
+			"GET /pastries":[{"request":{"name":"all","queryParameters":[{"name":"size","value":"S"}]},"response":{"name":"all","status":"200","mediaType":"application/json"}}],
+			"SUBSCRIBE pastries":[{"eventMessage":{"name":"laurent","content":"{}","headers":[{"name":"source","values":["test"]}]}}]}}`)
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	messages, err := c.ListMessages(context.Background(), "1")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, "S", messages["GET /pastries"][0].Request.QueryParameters[0].Value)
+	require.Equal(t, "application/json", messages["GET /pastries"][0].Response.MediaType)
+	event := messages["SUBSCRIBE pastries"][0]
+	require.Nil(t, event.Request)
+	require.Equal(t, "laurent", event.Name())
+	require.Equal(t, []string{"test"}, event.EventMessage.Headers[0].Values)
+}
+
+func TestServicesIterator(t *testing.T) {
+	var pages []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		pages = append(pages, r.URL.Query().Get("page"))
+		services := []client.Service{}
+		for i := page * size; i < min((page+1)*size, 250); i++ {
+			service := client.Service{ID: strconv.Itoa(i), Name: fmt.Sprintf("API %d", i), Version: "1.0", Type: client.ServiceTypeREST}
+			if i%50 == 0 {
+				service.Metadata = &client.Metadata{Labels: map[string]string{"domain": "pastry"}}
+			}
+			services = append(services, service)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(services))
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	services, err := c.ListServicesPage(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, services, 10)
+	require.Equal(t, "10", services[0].ID)
+
+	pages = nil
+	it := c.Services(context.Background(), client.ServiceFilter{Labels: map[string]string{"domain": "pastry"}})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"0", "50", "100", "150", "200"}, ids)
+	require.Equal(t, []string{"0", "1", "2"}, pages)
+
+	services, err = c.Services(context.Background(), client.ServiceFilter{Name: "api 24", Version: "1.0"}).All()
+	require.NoError(t, err)
+	require.Len(t, services, 11)
+
+	all, err := c.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, all, 250)
+}
+
+func TestTestResultsIterator(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/tests/service/1", r.URL.Path)
+		if r.URL.Query().Get("page") == "1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		results := make([]client.TestResult, 100)
+		require.NoError(t, json.NewEncoder(w).Encode(results))
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	results, err := c.TestResults(context.Background(), "1").All()
+	require.Len(t, results, 100)
+	var statusErr *client.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var attempts []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		attempts = append(attempts, string(body))
+		if len(attempts) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":"1"}`)
+	}))
+	defer api.Close()
+
+	// POST requests are not retried by default.
+	c, err := client.New(api.URL, client.WithRetryPolicy(client.Backoff{MaxRetries: 3, InitialInterval: time.Millisecond}))
+	require.NoError(t, err)
+	_, err = c.CreateTest(context.Background(), client.TestRequest{})
+	require.Error(t, err)
+	require.Len(t, attempts, 1)
+
+	attempts = nil
+	c, err = client.New(api.URL, client.WithRetryPolicy(client.Backoff{MaxRetries: 3, InitialInterval: time.Millisecond, ReplayNonIdempotent: true}))
+	require.NoError(t, err)
+
+	_, err = c.CreateTest(context.Background(), client.TestRequest{})
+	require.NoError(t, err)
+	require.Len(t, attempts, 3)
+	require.Equal(t, attempts[0], attempts[2])
+
+	attempts = nil
+	c, err = client.New(api.URL, client.WithRetryPolicy(client.Backoff{MaxRetries: 1, InitialInterval: time.Millisecond, ReplayNonIdempotent: true}))
+	require.NoError(t, err)
+	_, err = c.CreateTest(context.Background(), client.TestRequest{})
+	var statusErr *client.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	require.Len(t, attempts, 2)
+}
+
+func TestBackoff(t *testing.T) {
+	backoff := client.Backoff{MaxRetries: 4, InitialInterval: 100 * time.Millisecond, MaxInterval: 300 * time.Millisecond}
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+
+	var delays []time.Duration
+	for attempt := 1; ; attempt++ {
+		delay, retry := backoff.Retry(attempt, unavailable, nil)
+		if !retry {
+			break
+		}
+		delays = append(delays, delay)
+	}
+	require.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}, delays)
+
+	_, retry := backoff.Retry(1, &http.Response{StatusCode: http.StatusBadRequest}, nil)
+	require.False(t, retry)
+	_, retry = backoff.Retry(1, nil, errors.New("connection reset by peer"))
+	require.True(t, retry)
+	_, retry = client.Backoff{MaxRetries: 1, RetryableStatusCodes: []int{http.StatusConflict}}.Retry(1, unavailable, nil)
+	require.False(t, retry)
+
+	// Delays are clamped rather than overflowing, with or without a maximum interval.
+	unbounded := client.Backoff{MaxRetries: 100, InitialInterval: time.Second}
+	previous := time.Duration(0)
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay, retry := unbounded.Retry(attempt, unavailable, nil)
+		require.True(t, retry)
+		require.GreaterOrEqual(t, delay, previous)
+		previous = delay
+	}
+	require.Equal(t, time.Duration(math.MaxInt64), previous)
+}
+
+func TestSentinelErrors(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/artifact/upload":
+			w.WriteHeader(http.StatusBadRequest)
+		case "/api/services":
+			_, _ = io.WriteString(w, `[]`)
+		case "/api/secrets":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL)
+	require.NoError(t, err)
+
+	err = c.UploadArtifact(context.Background(), "pastries.yaml", strings.NewReader("openapi: 3.0.0"), true)
+	require.ErrorIs(t, err, client.ErrArtifactRejected)
+	require.EqualError(t, err, "unable to import artifact pastries.yaml, bad status code, actual 400, expected 201")
+
+	_, err = c.GetService(context.Background(), "1")
+	require.ErrorIs(t, err, client.ErrServiceNotFound)
+	_, err = c.FindService(context.Background(), "API Pastries", "0.0.1")
+	require.ErrorIs(t, err, client.ErrServiceNotFound)
+	err = c.DeleteService(context.Background(), "1")
+	require.ErrorIs(t, err, client.ErrServiceNotFound)
+
+	_, err = c.ListSecrets(context.Background())
+	require.ErrorIs(t, err, client.ErrAuthRequired)
+	require.NotErrorIs(t, err, client.ErrServiceNotFound)
+
+	_, err = c.GetTestResult(context.Background(), "1")
+	var statusErr *client.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Nil(t, statusErr.Err)
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := client.NewRateLimiter(50, 2)
+
+	start := time.Now()
+	for i := 0; i < 7; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+	// 2 calls of burst, then 5 calls at 50 per second.
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	slow := client.NewRateLimiter(0.1, 1)
+	require.NoError(t, slow.Wait(ctx))
+	require.ErrorIs(t, slow.Wait(ctx), context.DeadlineExceeded)
+
+	require.Panics(t, func() { client.NewRateLimiter(0, 1) })
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	var calls int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = io.WriteString(w, `[]`)
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL, client.WithRateLimiter(client.NewRateLimiter(20, 1)))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.ListSecrets(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, calls)
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestServiceCache(t *testing.T) {
+	var lookups int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/services":
+			lookups++
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case "/api/services/1":
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1","operations":[{"name":"GET /pastries","dispatcher":"URI_PARAMS"}]}`)
+		case "/api/artifact/upload":
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer api.Close()
+
+	cache := client.NewServiceCache()
+	c, err := client.New(api.URL, client.WithServiceCache(cache))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		service, err := c.FindService(context.Background(), "API Pastries", "0.0.1")
+		require.NoError(t, err)
+		require.Equal(t, client.DispatcherURIParams, service.Operation("GET /pastries").Dispatcher)
+		// Changing the returned service does not alter the cached one.
+		service.Operations[0].Dispatcher = client.DispatcherScript
+	}
+	require.Equal(t, 1, lookups)
+
+	require.NoError(t, c.UpdateOperation(context.Background(), "1", "GET /pastries", client.OperationOverride{}))
+	_, err = c.FindService(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, lookups)
+
+	require.NoError(t, c.UploadArtifact(context.Background(), "pastries.yaml", strings.NewReader("openapi: 3.0.0"), true))
+	_, err = c.FindService(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 3, lookups)
+
+	cache.Invalidate()
+	_, err = c.FindService(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 4, lookups)
+
+	_, err = c.FindService(context.Background(), "API Orders", "1.0")
+	require.ErrorIs(t, err, client.ErrServiceNotFound)
+}
+
+func TestStaticCredentials(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gateway-token" || r.Header.Get("X-API-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `[]`)
+	}))
+	defer api.Close()
+
+	_, err := client.New(api.URL, client.WithBearerToken(""))
+	require.Error(t, err)
+	_, err = client.New(api.URL, client.WithAPIKey("", "key"))
+	require.Error(t, err)
+
+	c, err := client.New(api.URL, client.WithBearerToken("gateway-token"), client.WithAPIKey("X-API-Key", "key"))
+	require.NoError(t, err)
+	_, err = c.ListSecrets(context.Background())
+	require.NoError(t, err)
+
+	c, err = client.New(api.URL, client.WithBearerToken("gateway-token"))
+	require.NoError(t, err)
+	_, err = c.ListSecrets(context.Background())
+	require.ErrorIs(t, err, client.ErrAuthRequired)
+}
+
+func TestStreamingUpload(t *testing.T) {
+	content := strings.Repeat("openapi: 3.0.0\n", 100000)
+	var attempts int
+	var contentLengths []int64
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		contentLengths = append(contentLengths, r.ContentLength)
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+		received, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "pastries.yaml", header.Filename)
+		require.Equal(t, content, string(received))
+		require.Equal(t, "true", r.FormValue("mainArtifact"))
+
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	c, err := client.New(api.URL, client.WithRetryPolicy(client.Backoff{MaxRetries: 1, ReplayNonIdempotent: true}))
+	require.NoError(t, err)
+
+	// Seekable readers of known size are sent with their content length, and replayed on retry.
+	require.NoError(t, c.UploadArtifact(context.Background(), "pastries.yaml", strings.NewReader(content), true))
+	require.Equal(t, 2, attempts)
+	require.Greater(t, contentLengths[0], int64(len(content)))
+	require.Equal(t, contentLengths[0], contentLengths[1])
+
+	// Other readers are streamed with an unknown content length, and not retried.
+	attempts = 0
+	contentLengths = nil
+	err = c.UploadArtifact(context.Background(), "pastries.yaml", io.MultiReader(strings.NewReader(content)), true)
+	require.ErrorIs(t, err, client.ErrArtifactRejected)
+	require.Equal(t, 1, attempts)
+	require.Equal(t, []int64{-1}, contentLengths)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrArtifactRejected is matched by errors of artifact or snapshot imports that Microcks rejected, e.g. as
+	// invalid or not supported.
+	ErrArtifactRejected = errors.New("artifact rejected")
+
+	// ErrServiceNotFound is matched by errors of calls about a Service that Microcks does not hold.
+	ErrServiceNotFound = errors.New("service not found")
+
+	// ErrAuthRequired is matched by errors of calls that Microcks rejected as unauthenticated or unauthorized, e.g.
+	// because of a missing or wrong service account.
+	ErrAuthRequired = errors.New("authentication required")
+)
+
+// StatusError represents an unexpected status code returned by the Microcks API.
+type StatusError struct {
+	// Action represents the action that failed, e.g. "import artifact".
+	Action string
+
+	// StatusCode represents the actual status code.
+	StatusCode int
+
+	// Expected represents the expected status code.
+	Expected int
+
+	// Err represents the sentinel error the status code means, if any, e.g. ErrArtifactRejected.
+	Err error
+}
+
+// NewStatusError creates a StatusError of given action, status codes and sentinel error, which may be nil.
+// ErrAuthRequired is used instead for 401 and 403 status codes.
+func NewStatusError(action string, statusCode, expected int, err error) *StatusError {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		err = ErrAuthRequired
+	}
+	return &StatusError{Action: action, StatusCode: statusCode, Expected: expected, Err: err}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unable to %s, bad status code, actual %d, expected %d", e.Action, e.StatusCode, e.Expected)
+}
+
+// Unwrap returns the sentinel error, so that errors.Is matches it.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// serviceNotFound makes a StatusError of a call about a Service match ErrServiceNotFound when Microcks answered 404.
+func serviceNotFound(err error) error {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		statusErr.Err = ErrServiceNotFound
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+// Iterator allows to iterate over the items of a paginated listing, fetching pages as needed. Use it as:
+//
+//	it := c.Services(ctx, client.ServiceFilter{})
+//	for it.Next() {
+//		service := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	ctx     context.Context
+	fetch   func(ctx context.Context, page, size int) ([]T, error)
+	match   func(T) bool
+	page    int
+	size    int
+	items   []T
+	current T
+	done    bool
+	err     error
+}
+
+// newIterator creates an Iterator over the pages returned by fetch, skipping items not matching match when set.
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context, page, size int) ([]T, error), match func(T) bool) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, match: match, size: pageSize}
+}
+
+// Next advances to the next item, fetching the next page when needed. It returns false when there is no more
+// item or on error, see Err.
+func (it *Iterator[T]) Next() bool {
+	for {
+		for len(it.items) > 0 {
+			it.current, it.items = it.items[0], it.items[1:]
+			if it.match == nil || it.match(it.current) {
+				return true
+			}
+		}
+		if it.done || it.err != nil {
+			return false
+		}
+
+		items, err := it.fetch(it.ctx, it.page, it.size)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page++
+		it.items = items
+		it.done = len(items) < it.size
+	}
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects the remaining items.
+func (it *Iterator[T]) All() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+// ServiceFilter represents a filter on listed Services. Zero fields match any Service.
+type ServiceFilter struct {
+	// Name represents a part of the Service name, matched case-insensitively.
+	Name string
+
+	// Version represents the exact Service version.
+	Version string
+
+	// Type represents the Service type.
+	Type ServiceType
+
+	// Labels represents labels the Service must have, with the same values.
+	Labels map[string]string
+}
+
+// Match tells if a Service matches the filter.
+func (f ServiceFilter) Match(service Service) bool {
+	if f.Name != "" && !strings.Contains(strings.ToLower(service.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+	if f.Version != "" && service.Version != f.Version {
+		return false
+	}
+	if f.Type != "" && service.Type != f.Type {
+		return false
+	}
+	for key, value := range f.Labels {
+		if service.Metadata == nil || service.Metadata.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
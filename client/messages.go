@@ -0,0 +1,142 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Header represents a header of a Request, Response or EventMessage.
+type Header struct {
+	// Name represents the header name.
+	Name string `json:"name"`
+
+	// Values represents the header values.
+	Values []string `json:"values"`
+}
+
+// Parameter represents a query parameter of a Request.
+type Parameter struct {
+	// Name represents the parameter name.
+	Name string `json:"name"`
+
+	// Value represents the parameter value.
+	Value string `json:"value"`
+}
+
+// Request represents a request example of an operation.
+type Request struct {
+	// ID represents the Request identifier.
+	ID string `json:"id"`
+
+	// Name represents the example name.
+	Name string `json:"name"`
+
+	// Content represents the request body.
+	Content string `json:"content,omitempty"`
+
+	// Headers represents the request headers.
+	Headers []Header `json:"headers,omitempty"`
+
+	// QueryParameters represents the request query parameters.
+	QueryParameters []Parameter `json:"queryParameters,omitempty"`
+}
+
+// Response represents a response example of an operation.
+type Response struct {
+	// ID represents the Response identifier.
+	ID string `json:"id"`
+
+	// Name represents the example name.
+	Name string `json:"name"`
+
+	// Content represents the response body.
+	Content string `json:"content,omitempty"`
+
+	// Headers represents the response headers.
+	Headers []Header `json:"headers,omitempty"`
+
+	// MediaType represents the response media type, e.g. "application/json".
+	MediaType string `json:"mediaType,omitempty"`
+
+	// Status represents the response status code, e.g. "200".
+	Status string `json:"status,omitempty"`
+
+	// DispatchCriteria represents the criteria a request must match to get this response, e.g. "/name=Millefeuille".
+	DispatchCriteria string `json:"dispatchCriteria,omitempty"`
+
+	// Fault tells if the response is a SOAP fault.
+	Fault bool `json:"isFault,omitempty"`
+}
+
+// EventMessage represents a message example of an event-driven operation.
+type EventMessage struct {
+	// ID represents the EventMessage identifier.
+	ID string `json:"id"`
+
+	// Name represents the example name.
+	Name string `json:"name"`
+
+	// Content represents the message payload.
+	Content string `json:"content,omitempty"`
+
+	// Headers represents the message headers.
+	Headers []Header `json:"headers,omitempty"`
+
+	// MediaType represents the message media type, e.g. "application/json".
+	MediaType string `json:"mediaType,omitempty"`
+}
+
+// Message represents an example of an operation: a Request and Response pair, or an EventMessage for
+// event-driven operations.
+type Message struct {
+	// Request represents the request example, nil for event-driven operations.
+	Request *Request `json:"request,omitempty"`
+
+	// Response represents the response example, nil for event-driven operations.
+	Response *Response `json:"response,omitempty"`
+
+	// EventMessage represents the message example of event-driven operations.
+	EventMessage *EventMessage `json:"eventMessage,omitempty"`
+}
+
+// Name returns the example name.
+func (m Message) Name() string {
+	switch {
+	case m.Response != nil:
+		return m.Response.Name
+	case m.Request != nil:
+		return m.Request.Name
+	case m.EventMessage != nil:
+		return m.EventMessage.Name
+	}
+	return ""
+}
+
+// ListMessages lists the examples of the Service having given identifier, by operation name.
+func (c *Client) ListMessages(ctx context.Context, serviceID string) (map[string][]Message, error) {
+	var view struct {
+		MessagesMap map[string][]Message `json:"messagesMap"`
+	}
+	path := "/api/services/" + url.PathEscape(serviceID) + "?messages=true"
+	if err := c.call(ctx, "get messages of service "+serviceID, http.MethodGet, path, nil, http.StatusOK, &view); err != nil {
+		return nil, serviceNotFound(err)
+	}
+
+	return view.MessagesMap, nil
+}
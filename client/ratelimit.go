@@ -0,0 +1,107 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter represents a limiter of the rate of calls to Microcks, e.g. NewRateLimiter or a *rate.Limiter of
+// golang.org/x/time/rate.
+type RateLimiter interface {
+	// Wait blocks until a call is allowed, or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// NewRateLimiter creates a RateLimiter allowing requestsPerSecond calls on average, with bursts of up to burst
+// calls. burst is at least 1. It panics if requestsPerSecond is not positive.
+func NewRateLimiter(requestsPerSecond float64, burst int) RateLimiter {
+	if requestsPerSecond <= 0 {
+		panic("non-positive rate for NewRateLimiter")
+	}
+	burst = max(burst, 1)
+	return &tokenBucket{rate: requestsPerSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// tokenBucket represents a RateLimiter refilling tokens at a constant rate. Tokens go negative when calls wait for
+// them, so that waiting calls are allowed in turn.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mutex.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the token back, as the call is not made.
+		b.mutex.Lock()
+		b.tokens++
+		b.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// WithRateLimiter sets the limiter of the rate of calls, e.g. so that a massively parallel test matrix does not
+// overwhelm Microcks. Every attempt of retried calls is limited.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) error {
+		c.rateLimiter = limiter
+		return nil
+	}
+}
+
+// NewRateLimitTransport creates a transport sending requests through next once allowed by limiter, e.g. to limit
+// calls made outside of a Client. next is http.DefaultTransport when nil.
+func NewRateLimitTransport(next http.RoundTripper, limiter RateLimiter) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitTransport{next: next, limiter: limiter}
+}
+
+// rateLimitTransport represents a transport waiting for a RateLimiter before sending requests.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter RateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// DefaultRetryableStatusCodes represents the status codes retried by a Backoff policy when none are set: throttling
+// and gateway errors, as returned while Microcks or a proxy in front of it is warming up.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy represents a policy deciding whether and when a failed call to Microcks is retried.
+type RetryPolicy interface {
+	// Retry is called after the given attempt, counted from 1, returned response or err. It returns the delay
+	// before the next attempt, and false to stop retrying and return the response or error.
+	Retry(attempt int, response *http.Response, err error) (time.Duration, bool)
+}
+
+// NonIdempotentRetryPolicy represents a RetryPolicy also replaying non-idempotent requests, e.g. POST requests
+// launching tests or uploading artifacts. Other policies only retry idempotent requests, as replaying a request
+// that reached Microcks before failing, e.g. with a 502 from a proxy, may launch a duplicate test or import.
+type NonIdempotentRetryPolicy interface {
+	RetryPolicy
+
+	// RetryNonIdempotent tells if non-idempotent requests are retried too.
+	RetryNonIdempotent() bool
+}
+
+// Backoff represents a RetryPolicy retrying connection errors and retryable status codes with an exponential
+// backoff.
+type Backoff struct {
+	// MaxRetries represents the maximum number of retries, after the first attempt.
+	MaxRetries int
+
+	// InitialInterval represents the delay before the first retry, doubled at each retry.
+	InitialInterval time.Duration
+
+	// MaxInterval represents the maximum delay between retries, unbounded when 0.
+	MaxInterval time.Duration
+
+	// RetryableStatusCodes represents the retried status codes, DefaultRetryableStatusCodes when nil.
+	RetryableStatusCodes []int
+
+	// ReplayNonIdempotent tells if non-idempotent requests, e.g. POST, are retried too.
+	ReplayNonIdempotent bool
+}
+
+// Retry implements RetryPolicy.
+func (b Backoff) Retry(attempt int, response *http.Response, err error) (time.Duration, bool) {
+	if attempt > b.MaxRetries {
+		return 0, false
+	}
+	if err == nil {
+		codes := b.RetryableStatusCodes
+		if codes == nil {
+			codes = DefaultRetryableStatusCodes
+		}
+		if !slices.Contains(codes, response.StatusCode) {
+			return 0, false
+		}
+	}
+
+	// Doubling is clamped instead of overflowing to a negative or zero delay.
+	delay := b.InitialInterval
+	for i := 1; i < attempt && delay > 0; i++ {
+		if delay > math.MaxInt64/2 {
+			delay = math.MaxInt64
+			break
+		}
+		delay *= 2
+	}
+	if b.MaxInterval > 0 && delay > b.MaxInterval {
+		delay = b.MaxInterval
+	}
+	return delay, true
+}
+
+// RetryNonIdempotent implements NonIdempotentRetryPolicy.
+func (b Backoff) RetryNonIdempotent() bool {
+	return b.ReplayNonIdempotent
+}
+
+// WithRetryPolicy sets the policy retrying failed calls, e.g. a Backoff. Only idempotent requests are replayed,
+// unless the policy is a NonIdempotentRetryPolicy opting in, and never those having a body that cannot be read again.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// NewRetryTransport creates a transport sending requests through next, retrying them as decided by policy, e.g. to
+// retry calls made outside of a Client. next is http.DefaultTransport when nil.
+func NewRetryTransport(next http.RoundTripper, policy RetryPolicy) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{next: next, policy: policy}
+}
+
+// retryTransport represents a transport retrying requests as decided by a RetryPolicy.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !isIdempotent(req.Method) {
+		policy, ok := t.policy.(NonIdempotentRetryPolicy)
+		replayable = replayable && ok && policy.RetryNonIdempotent()
+	}
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		response, err := t.next.RoundTrip(attemptReq)
+		if !replayable {
+			return response, err
+		}
+		delay, retry := t.policy.Retry(attempt, response, err)
+		if !retry {
+			return response, err
+		}
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		attemptReq = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if attemptReq.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// isIdempotent tells if requests having given method are idempotent, as defined by RFC 9110.
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
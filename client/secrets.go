@@ -0,0 +1,53 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CreateSecret creates a secret and returns it with its identifier.
+func (c *Client) CreateSecret(ctx context.Context, s Secret) (*Secret, error) {
+	created := &Secret{}
+	if err := c.call(ctx, "create secret "+s.Name, http.MethodPost, "/api/secrets", s, http.StatusCreated, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListSecrets lists the secrets.
+func (c *Client) ListSecrets(ctx context.Context) ([]Secret, error) {
+	var secrets []Secret
+	if err := c.call(ctx, "get secrets", http.MethodGet, "/api/secrets", nil, http.StatusOK, &secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// UpdateSecret updates the secret having given identifier.
+func (c *Client) UpdateSecret(ctx context.Context, id string, s Secret) error {
+	s.Id = &id
+	return c.call(ctx, "update secret "+id, http.MethodPut, "/api/secrets/"+url.PathEscape(id), s, http.StatusOK, nil)
+}
+
+// DeleteSecret deletes the secret having given identifier.
+func (c *Client) DeleteSecret(ctx context.Context, id string) error {
+	return c.call(ctx, "delete secret "+id, http.MethodDelete, "/api/secrets/"+url.PathEscape(id), nil, http.StatusOK, nil)
+}
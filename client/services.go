@@ -0,0 +1,349 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// pageSize represents the size of pages used to list all the items of an API.
+const pageSize = 100
+
+// statsDayLayout represents the layout of the days of invocation statistics.
+const statsDayLayout = "20060102"
+
+// ServiceType represents the type of a Service.
+type ServiceType string
+
+const (
+	// ServiceTypeREST represents a REST API, e.g. imported from an OpenAPI contract.
+	ServiceTypeREST ServiceType = "REST"
+	// ServiceTypeSOAP represents a SOAP over HTTP API.
+	ServiceTypeSOAP ServiceType = "SOAP_HTTP"
+	// ServiceTypeGraphQL represents a GraphQL API.
+	ServiceTypeGraphQL ServiceType = "GRAPHQL"
+	// ServiceTypeGRPC represents a gRPC API.
+	ServiceTypeGRPC ServiceType = "GRPC"
+	// ServiceTypeEvent represents an event-driven API, e.g. imported from an AsyncAPI contract.
+	ServiceTypeEvent ServiceType = "EVENT"
+)
+
+// Dispatcher represents the strategy used by Microcks to find the mock response of an operation request.
+type Dispatcher string
+
+const (
+	// DispatcherURIParts dispatches on path parameters, DispatcherRules listing their names, e.g. "name".
+	DispatcherURIParts Dispatcher = "URI_PARTS"
+	// DispatcherURIParams dispatches on query parameters, DispatcherRules listing their names, e.g. "page && size".
+	DispatcherURIParams Dispatcher = "URI_PARAMS"
+	// DispatcherURIElements dispatches on path and query parameters.
+	DispatcherURIElements Dispatcher = "URI_ELEMENTS"
+	// DispatcherQueryArgs dispatches on GraphQL or gRPC arguments.
+	DispatcherQueryArgs Dispatcher = "QUERY_ARGS"
+	// DispatcherJSONBody dispatches on the request body, DispatcherRules holding a JSON evaluation specification.
+	DispatcherJSONBody Dispatcher = "JSON_BODY"
+	// DispatcherScript dispatches with a Groovy script, DispatcherRules holding the script.
+	DispatcherScript Dispatcher = "SCRIPT"
+	// DispatcherFallback dispatches with another dispatcher, falling back to a default response.
+	DispatcherFallback Dispatcher = "FALLBACK"
+	// DispatcherProxy forwards requests to a real endpoint.
+	DispatcherProxy Dispatcher = "PROXY"
+	// DispatcherProxyFallback dispatches with another dispatcher, forwarding to a real endpoint when no response matches.
+	DispatcherProxyFallback Dispatcher = "PROXY_FALLBACK"
+)
+
+// Service represents a Service imported in Microcks.
+type Service struct {
+	// ID represents the Service identifier.
+	ID string `json:"id"`
+
+	// Name represents the Service name.
+	Name string `json:"name"`
+
+	// Version represents the Service version.
+	Version string `json:"version"`
+
+	// Type represents the Service type.
+	Type ServiceType `json:"type,omitempty"`
+
+	// SourceArtifact represents the name of the main artifact the Service was imported from.
+	SourceArtifact string `json:"sourceArtifact,omitempty"`
+
+	// Metadata represents the Service metadata, including its labels.
+	Metadata *Metadata `json:"metadata,omitempty"`
+
+	// Operations represents the Service operations.
+	Operations []Operation `json:"operations,omitempty"`
+}
+
+// Metadata represents the metadata of a Service.
+type Metadata struct {
+	// CreatedOn represents the creation time, in milliseconds since epoch.
+	CreatedOn int64 `json:"createdOn,omitempty"`
+
+	// LastUpdate represents the last update time, in milliseconds since epoch.
+	LastUpdate int64 `json:"lastUpdate,omitempty"`
+
+	// Labels represents the labels, e.g. "domain" or "status".
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Operation represents an operation of a Service.
+type Operation struct {
+	// Name represents the operation name, e.g. "GET /pastries/{name}".
+	Name string `json:"name"`
+
+	// Method represents the HTTP method of the operation, for REST Services.
+	Method string `json:"method,omitempty"`
+
+	// Action represents the action of the operation, for SOAP or event-driven Services.
+	Action string `json:"action,omitempty"`
+
+	// InputName represents the name of the operation input.
+	InputName string `json:"inputName,omitempty"`
+
+	// OutputName represents the name of the operation output.
+	OutputName string `json:"outputName,omitempty"`
+
+	// Dispatcher represents the dispatching strategy of the operation.
+	Dispatcher Dispatcher `json:"dispatcher,omitempty"`
+
+	// DispatcherRules represents the dispatching rules, whose format depends on Dispatcher.
+	DispatcherRules string `json:"dispatcherRules,omitempty"`
+
+	// DefaultDelay represents the delay applied to mock responses, in milliseconds.
+	DefaultDelay int `json:"defaultDelay,omitempty"`
+
+	// ResourcePaths represents the paths of mock responses, e.g. "/pastries/Millefeuille".
+	ResourcePaths []string `json:"resourcePaths,omitempty"`
+}
+
+// Operation returns the operation of the Service having given name, or nil if not found.
+func (s *Service) Operation(name string) *Operation {
+	for i := range s.Operations {
+		if s.Operations[i].Name == name {
+			return &s.Operations[i]
+		}
+	}
+	return nil
+}
+
+// ResourceType represents the type of a Resource.
+type ResourceType string
+
+const (
+	// ResourceTypeOpenAPISpec represents an OpenAPI specification.
+	ResourceTypeOpenAPISpec ResourceType = "OPEN_API_SPEC"
+	// ResourceTypeAsyncAPISpec represents an AsyncAPI specification.
+	ResourceTypeAsyncAPISpec ResourceType = "ASYNC_API_SPEC"
+	// ResourceTypeJSONSchema represents a JSON schema referenced by a specification.
+	ResourceTypeJSONSchema ResourceType = "JSON_SCHEMA"
+	// ResourceTypeProtobufSchema represents a Protocol Buffers schema.
+	ResourceTypeProtobufSchema ResourceType = "PROTOBUF_SCHEMA"
+	// ResourceTypeGraphQLSchema represents a GraphQL schema.
+	ResourceTypeGraphQLSchema ResourceType = "GRAPHQL_SCHEMA"
+	// ResourceTypeWSDL represents a WSDL.
+	ResourceTypeWSDL ResourceType = "WSDL"
+	// ResourceTypePostmanCollection represents a Postman collection.
+	ResourceTypePostmanCollection ResourceType = "POSTMAN_COLLECTION"
+)
+
+// Resource represents a contract or schema file held by Microcks for a Service.
+type Resource struct {
+	// ID represents the Resource identifier.
+	ID string `json:"id"`
+
+	// Name represents the Resource name, e.g. "API Pastries-0.0.1.yaml".
+	Name string `json:"name"`
+
+	// Path represents the Resource path, relative to the main artifact, for referenced resources.
+	Path string `json:"path,omitempty"`
+
+	// Content represents the Resource content.
+	Content string `json:"content"`
+
+	// Type represents the Resource type.
+	Type ResourceType `json:"type"`
+
+	// ServiceID represents the identifier of the Service owning the Resource.
+	ServiceID string `json:"serviceId"`
+
+	// SourceArtifact represents the name of the artifact the Resource was imported from.
+	SourceArtifact string `json:"sourceArtifact,omitempty"`
+
+	// MainArtifact tells if the Resource comes from a main artifact.
+	MainArtifact bool `json:"mainArtifact"`
+}
+
+// ListServices lists all the Services imported in Microcks.
+func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
+	return c.Services(ctx, ServiceFilter{}).All()
+}
+
+// ListServicesPage lists a page of the Services imported in Microcks, pages being numbered from 0.
+func (c *Client) ListServicesPage(ctx context.Context, page, size int) ([]Service, error) {
+	var services []Service
+	path := fmt.Sprintf("/api/services?page=%d&size=%d", page, size)
+	if err := c.call(ctx, "get services", http.MethodGet, path, nil, http.StatusOK, &services); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// Services iterates over the Services imported in Microcks matching filter, fetching them page by page.
+func (c *Client) Services(ctx context.Context, filter ServiceFilter) *Iterator[Service] {
+	return newIterator(ctx, c.ListServicesPage, filter.Match)
+}
+
+// GetService gets the Service having given identifier, with its operations.
+func (c *Client) GetService(ctx context.Context, id string) (*Service, error) {
+	service := &Service{}
+	path := "/api/services/" + url.PathEscape(id) + "?messages=false"
+	if err := c.call(ctx, "get service "+id, http.MethodGet, path, nil, http.StatusOK, service); err != nil {
+		return nil, serviceNotFound(err)
+	}
+
+	return service, nil
+}
+
+// FindService finds the Service having given name and version, with its operations. It is looked up once when a
+// ServiceCache is set.
+func (c *Client) FindService(ctx context.Context, name, version string) (*Service, error) {
+	if c.serviceCache != nil {
+		if service := c.serviceCache.get(name, version); service != nil {
+			return service, nil
+		}
+	}
+
+	services, err := c.ListServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range services {
+		if s.Name == name && s.Version == version {
+			service, err := c.GetService(ctx, s.ID)
+			if err == nil && c.serviceCache != nil {
+				c.serviceCache.put(service)
+			}
+			return service, err
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find service %s:%s: %w", name, version, ErrServiceNotFound)
+}
+
+// OperationOverride represents the mocking settings of an operation that can be changed without re-importing
+// the artifact.
+type OperationOverride struct {
+	// Dispatcher represents the dispatching strategy of the operation.
+	Dispatcher Dispatcher `json:"dispatcher"`
+
+	// DispatcherRules represents the dispatching rules, whose format depends on Dispatcher.
+	DispatcherRules string `json:"dispatcherRules"`
+
+	// DefaultDelay represents the delay applied to mock responses, in milliseconds.
+	DefaultDelay int `json:"defaultDelay"`
+}
+
+// Override returns the current mocking settings of the operation, e.g. to restore them after an UpdateOperation.
+func (o *Operation) Override() OperationOverride {
+	return OperationOverride{
+		Dispatcher:      o.Dispatcher,
+		DispatcherRules: o.DispatcherRules,
+		DefaultDelay:    o.DefaultDelay,
+	}
+}
+
+// UpdateOperation replaces the mocking settings of the operation having given name, of the Service having given
+// identifier. Every setting is replaced: start from Operation.Override to only change some of them.
+func (c *Client) UpdateOperation(ctx context.Context, serviceID, operationName string, override OperationOverride) error {
+	path := "/api/services/" + url.PathEscape(serviceID) + "/operation?operationName=" + url.QueryEscape(operationName)
+	defer c.invalidateService(serviceID)
+	return serviceNotFound(c.call(ctx, "update operation "+operationName, http.MethodPut, path, override, http.StatusOK, nil))
+}
+
+// ListResources lists the Resources of the Service having given identifier.
+func (c *Client) ListResources(ctx context.Context, serviceID string) ([]Resource, error) {
+	var resources []Resource
+	path := "/api/resources/service/" + url.PathEscape(serviceID)
+	if err := c.call(ctx, "get resources of service "+serviceID, http.MethodGet, path, nil, http.StatusOK, &resources); err != nil {
+		return nil, serviceNotFound(err)
+	}
+
+	return resources, nil
+}
+
+// DeleteService deletes the Service having given identifier.
+func (c *Client) DeleteService(ctx context.Context, id string) error {
+	defer c.invalidateService(id)
+	return serviceNotFound(c.call(ctx, "delete service "+id, http.MethodDelete, "/api/services/"+url.PathEscape(id), nil, http.StatusOK, nil))
+}
+
+// InvocationStats represents the daily invocation statistics of a Service.
+type InvocationStats struct {
+	// ServiceName represents the name of the Service.
+	ServiceName string `json:"serviceName"`
+
+	// ServiceVersion represents the version of the Service.
+	ServiceVersion string `json:"serviceVersion"`
+
+	// Day represents the day of statistics, formatted as yyyyMMdd.
+	Day string `json:"day"`
+
+	// DailyCount represents the number of invocations during the day.
+	DailyCount int `json:"dailyCount"`
+
+	// HourlyCount represents the number of invocations per hour of the day (key is the hour: "0" to "23").
+	HourlyCount map[string]int `json:"hourlyCount"`
+
+	// MinuteCount represents the number of invocations per minute of the day (key is the minute: "0" to "1439").
+	MinuteCount map[string]int `json:"minuteCount"`
+}
+
+// InvocationStats gets the invocation statistics of given Service for the day of date, in UTC. Statistics are
+// empty when the Service has not been invoked that day.
+func (c *Client) InvocationStats(ctx context.Context, serviceName, serviceVersion string, date time.Time) (*InvocationStats, error) {
+	day := date.UTC().Format(statsDayLayout)
+	stats := &InvocationStats{
+		ServiceName:    serviceName,
+		ServiceVersion: serviceVersion,
+		Day:            day,
+	}
+
+	path := fmt.Sprintf("/api/metrics/invocations/%s/%s?day=%s", url.PathEscape(serviceName), url.PathEscape(serviceVersion), day)
+	err := c.call(ctx, "get invocation statistics", http.MethodGet, path, nil, http.StatusOK, stats)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		// No statistics yet for this service.
+		return stats, nil
+	}
+	if errors.Is(err, io.EOF) {
+		// No statistics yet either, served as an empty body.
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
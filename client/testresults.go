@@ -0,0 +1,63 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateTest launches a contract test, returning its result as long as it is in progress.
+func (c *Client) CreateTest(ctx context.Context, testRequest TestRequest) (*TestResult, error) {
+	testResult := &TestResult{}
+	if err := c.call(ctx, "launch test", http.MethodPost, "/api/tests", testRequest, http.StatusCreated, testResult); err != nil {
+		return nil, err
+	}
+
+	return testResult, nil
+}
+
+// GetTestResult gets the result of the test having given identifier.
+func (c *Client) GetTestResult(ctx context.Context, id string) (*TestResult, error) {
+	testResult := &TestResult{}
+	if err := c.call(ctx, "get test result "+id, http.MethodGet, "/api/tests/"+url.PathEscape(id), nil, http.StatusOK, testResult); err != nil {
+		return nil, err
+	}
+
+	return testResult, nil
+}
+
+// ListTestResults lists the results of the tests of the Service having given identifier, most recent first, by
+// pages of given size.
+func (c *Client) ListTestResults(ctx context.Context, serviceID string, page, size int) ([]TestResult, error) {
+	var testResults []TestResult
+	path := fmt.Sprintf("/api/tests/service/%s?page=%d&size=%d", url.PathEscape(serviceID), page, size)
+	if err := c.call(ctx, "get test results", http.MethodGet, path, nil, http.StatusOK, &testResults); err != nil {
+		return nil, err
+	}
+
+	return testResults, nil
+}
+
+// TestResults iterates over the results of the tests of the Service having given identifier, most recent first,
+// fetching them page by page.
+func (c *Client) TestResults(ctx context.Context, serviceID string) *Iterator[TestResult] {
+	return newIterator(ctx, func(ctx context.Context, page, size int) ([]TestResult, error) {
+		return c.ListTestResults(ctx, serviceID, page, size)
+	}, nil)
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// diagnosticsTestResults represents the number of most recent test results collected per Service.
+const diagnosticsTestResults = 10
+
+// CollectDiagnostics gathers the container Info, logs, environment with sensitive values redacted, the imported
+// Services, their most recent test results and invocation statistics into a zip file of dir, e.g. to attach it to
+// a bug report. Collection is best effort: what cannot be collected is reported in the "errors.txt" entry.
+// Logs and environment are not available for a remote Microcks instance. It returns the path of the zip file.
+func (container *MicrocksContainer) CollectDiagnostics(ctx context.Context, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating diagnostics directory: %w", err)
+	}
+
+	name := "remote"
+	if !container.IsRemote() {
+		name = container.GetContainerID()
+		if len(name) > 12 {
+			name = name[:12]
+		}
+	}
+	fileName := filepath.Join(dir, fmt.Sprintf("microcks-diagnostics-%s-%s.zip", name, time.Now().Format("20060102-150405")))
+	file, err := os.Create(fileName)
+	if err != nil {
+		return "", fmt.Errorf("error creating diagnostics file: %w", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	var errs []error
+	collect := func(entry string, write func(w io.Writer) error) {
+		w, err := archive.Create(entry)
+		if err == nil {
+			err = write(w)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry, err))
+		}
+	}
+
+	if !container.IsRemote() {
+		collect("logs.txt", func(w io.Writer) error {
+			logs, err := container.Logs(ctx)
+			if err != nil {
+				return err
+			}
+			defer logs.Close()
+			_, err = io.Copy(w, logs)
+			return err
+		})
+		collect("env.txt", func(w io.Writer) error {
+			return container.writeRedactedEnv(ctx, w)
+		})
+	}
+
+	collect("info.json", func(w io.Writer) error {
+		info, err := container.Info(ctx)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, info)
+	})
+
+	services, err := container.listServices(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	collect("services.json", func(w io.Writer) error {
+		return writeJSON(w, services)
+	})
+	for _, s := range services {
+		collect(fmt.Sprintf("tests/%s-%s.json", s.Name, s.Version), func(w io.Writer) error {
+			return container.writeTestResults(ctx, s.ID, w)
+		})
+		collect(fmt.Sprintf("invocations/%s-%s.json", s.Name, s.Version), func(w io.Writer) error {
+			stats, err := container.ServiceInvocationStats(ctx, s.Name, s.Version)
+			if err != nil {
+				return err
+			}
+			return writeJSON(w, stats)
+		})
+	}
+
+	if len(errs) > 0 {
+		collect("errors.txt", func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, errors.Join(errs...))
+			return err
+		})
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", fmt.Errorf("error writing diagnostics file: %w", err)
+	}
+	return fileName, nil
+}
+
+// writeRedactedEnv writes the container environment to w, one sorted variable per line, sensitive values redacted.
+func (container *MicrocksContainer) writeRedactedEnv(ctx context.Context, w io.Writer) error {
+	inspect, err := container.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+
+	env := redactedEnv(inspect.Config.Env)
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", key, env[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTestResults writes the most recent test results of the Service having given identifier to w.
+func (container *MicrocksContainer) writeTestResults(ctx context.Context, serviceID string, w io.Writer) error {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	testResults, err := c.ListTestResults(ctx, serviceID, 0, diagnosticsTestResults)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, testResults)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
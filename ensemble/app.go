@@ -0,0 +1,179 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	microcks "microcks.io/testcontainers-go"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/redact"
+)
+
+// NetworkEndpoints represents the members endpoints reachable from the ensemble network. It is the data given
+// to the environment templates of the application container, e.g. {{ .RestMockEndpoint "API Pastries" "0.0.1" }}.
+type NetworkEndpoints struct {
+	ec *MicrocksContainersEnsemble
+}
+
+// HttpEndpoint returns the Microcks HTTP endpoint within the ensemble network, or the remote Microcks one.
+func (n NetworkEndpoints) HttpEndpoint() string {
+	if n.ec.microcksContainer != nil && n.ec.microcksContainer.IsRemote() {
+		endpoint, _ := n.ec.microcksContainer.HttpEndpoint(context.Background())
+		return endpoint
+	}
+	scheme := "http"
+	if n.ec.microcksContainer != nil && n.ec.microcksContainer.CACertificate() != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s:%s", scheme, microcks.DefaultNetworkAlias, nat.Port(microcks.DefaultHttpPort).Port())
+}
+
+// RestMockEndpoint returns the mock endpoint of a REST Service within the ensemble network.
+func (n NetworkEndpoints) RestMockEndpoint(service, version string) string {
+	return fmt.Sprintf("%s/rest/%s/%s", n.HttpEndpoint(), url.PathEscape(service), url.PathEscape(version))
+}
+
+// SoapMockEndpoint returns the mock endpoint of a SOAP Service within the ensemble network.
+func (n NetworkEndpoints) SoapMockEndpoint(service, version string) string {
+	return fmt.Sprintf("%s/soap/%s/%s", n.HttpEndpoint(), url.PathEscape(service), url.PathEscape(version))
+}
+
+// GraphQLMockEndpoint returns the mock endpoint of a GraphQL Service within the ensemble network.
+func (n NetworkEndpoints) GraphQLMockEndpoint(service, version string) string {
+	return fmt.Sprintf("%s/graphql/%s/%s", n.HttpEndpoint(), url.PathEscape(service), url.PathEscape(version))
+}
+
+// GrpcMockEndpoint returns the gRPC mock endpoint within the ensemble network.
+func (n NetworkEndpoints) GrpcMockEndpoint() string {
+	if n.ec.microcksContainer != nil && n.ec.microcksContainer.IsRemote() {
+		endpoint, _ := n.ec.microcksContainer.GrpcMockEndpoint(context.Background())
+		return endpoint
+	}
+	return fmt.Sprintf("grpc://%s:%s", microcks.DefaultNetworkAlias, nat.Port(microcks.DefaultGrpcPort).Port())
+}
+
+// KafkaBootstrapServers returns the bootstrap servers of the Kafka broker started by the ensemble.
+func (n NetworkEndpoints) KafkaBootstrapServers() string {
+	return KafkaNetworkAlias + ":" + KafkaBrokerPort
+}
+
+// KafkaMockTopic returns the mock topic of a Kafka Service.
+func (n NetworkEndpoints) KafkaMockTopic(service, version, operationName string) string {
+	return n.ec.KafkaMockTopic(service, version, operationName)
+}
+
+// appContainer represents the application under test settings.
+type appContainer struct {
+	request testcontainers.ContainerRequest
+	alias   string
+}
+
+// WithAppContainer starts the application under test on the ensemble network, with given alias, once all
+// members are started. Its environment values are templates rendered with NetworkEndpoints, so that mock
+// endpoints can be injected, e.g. "PASTRIES_URL": `{{ .RestMockEndpoint "API Pastries" "0.0.1" }}`.
+func WithAppContainer(req testcontainers.ContainerRequest, alias string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.app = &appContainer{request: req, alias: alias}
+		return nil
+	}
+}
+
+// GetAppContainer returns the application under test container, or nil when not provided.
+func (ec *MicrocksContainersEnsemble) GetAppContainer() testcontainers.Container {
+	return ec.appContainer
+}
+
+// AppEndpoint returns the application under test endpoint within the ensemble network, using its first exposed
+// port, to be used as test endpoint by Microcks, e.g. "http://my-app:8080".
+func (ec *MicrocksContainersEnsemble) AppEndpoint() string {
+	if ec.app == nil || len(ec.app.request.ExposedPorts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("http://%s:%s", ec.app.alias, nat.Port(ec.app.request.ExposedPorts[0]).Port())
+}
+
+// startApp renders the application environment and starts it on the ensemble network.
+func (ec *MicrocksContainersEnsemble) startApp(ctx context.Context) error {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: ec.app.request,
+		Started:          true,
+	}
+
+	env := make(map[string]string, len(req.Env))
+	endpoints := NetworkEndpoints{ec: ec}
+	for key, value := range req.Env {
+		rendered, err := renderEnv(key, value, endpoints)
+		if err != nil {
+			return err
+		}
+		env[key] = rendered
+	}
+	req.Env = env
+
+	if err := network.WithNetwork([]string{ec.app.alias}, ec.network).Customize(&req); err != nil {
+		return err
+	}
+	if err := microcks.WithLabels(ec.labels).Customize(&req); err != nil {
+		return err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return err
+	}
+	if ec.staticHostResolution {
+		staticHosts, err := ec.staticHosts(ctx)
+		if err != nil {
+			return err
+		}
+		if err := staticHosts.Customize(&req); err != nil {
+			return err
+		}
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
+	if err != nil {
+		return fmt.Errorf("error starting application container (%s): %w", redact.Request(req), err)
+	}
+	ec.appContainer = container
+	return nil
+}
+
+func renderEnv(key, value string, endpoints NetworkEndpoints) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New(key).Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s environment template: %w", key, err)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, endpoints); err != nil {
+		return "", fmt.Errorf("error rendering %s environment template: %w", key, err)
+	}
+	return rendered.String(), nil
+}
@@ -17,15 +17,36 @@ package async
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"microcks.io/testcontainers-go/ensemble/async/connection/amazonservice"
+	"microcks.io/testcontainers-go/ensemble/async/connection/amqp"
+	"microcks.io/testcontainers-go/ensemble/async/connection/googlepubsub"
 	"microcks.io/testcontainers-go/ensemble/async/connection/kafka"
+	"microcks.io/testcontainers-go/ensemble/async/connection/mqtt"
+	"microcks.io/testcontainers-go/health"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/logs"
+	"microcks.io/testcontainers-go/internal/platform"
+	"microcks.io/testcontainers-go/internal/proxy"
+	"microcks.io/testcontainers-go/internal/redact"
+	"microcks.io/testcontainers-go/internal/registry"
+	"microcks.io/testcontainers-go/internal/resources"
+	"microcks.io/testcontainers-go/internal/startup"
+	"microcks.io/testcontainers-go/metrics"
 )
 
 const (
+	// DefaultImage represents the default Microcks Async Minion image.
 	DefaultImage = "quay.io/microcks/microcks-uber-async-minion:latest"
 
 	// DefaultHttpPort represents the default Microcks Async Minion HTTP port
@@ -33,8 +54,23 @@ const (
 
 	// DefaultNetworkAlias represents the default network alias of the the PostmanContainer
 	DefaultNetworkAlias = "microcks-async-minion"
+
+	// HealthPath represents the path of the Microcks Async Minion health endpoint.
+	HealthPath = "/q/health"
 )
 
+// defaultMicrocksHostPort represents the default host and port of Microcks, reached through its network alias.
+const defaultMicrocksHostPort = "microcks:8080"
+
+// publishedMessagesMetric represents the minion metric counting published mock messages.
+const publishedMessagesMetric = "microcks_async_minion_published_messages"
+
+// publishErrorsMetric represents the minion metric counting mock messages that failed to be published.
+const publishErrorsMetric = "microcks_async_minion_publish_errors"
+
+// protocolLabel represents the label of minion metrics holding the protocol, e.g. "kafka" or "mqtt".
+const protocolLabel = "protocol"
+
 // Option represents an option to pass to the minion
 type Option func(*MicrocksAsyncMinionContainer) error
 
@@ -56,34 +92,64 @@ type MicrocksAsyncMinionContainer struct {
 }
 
 // RunContainer creates an instance of the MicrocksAsyncMinionContainer type.
+// Deprecated: use Run with WithMicrocksHostPort instead.
 func RunContainer(ctx context.Context, microcksHostPort string, opts ...testcontainers.ContainerCustomizer) (*MicrocksAsyncMinionContainer, error) {
+	return Run(ctx, DefaultImage, append([]testcontainers.ContainerCustomizer{WithMicrocksHostPort(microcksHostPort)}, opts...)...)
+}
+
+// Run creates an instance of the MicrocksAsyncMinionContainer type, using given image.
+// Microcks is expected at microcks:8080 unless WithMicrocksHostPort is used.
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*MicrocksAsyncMinionContainer, error) {
 	req := testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
-			Image:        DefaultImage,
+			Image:        img,
 			ExposedPorts: []string{DefaultHttpPort},
 			WaitingFor:   wait.ForLog("Profile prod activated"),
 			Env: map[string]string{
-				"MICROCKS_HOST_PORT": microcksHostPort,
+				"MICROCKS_HOST_PORT": defaultMicrocksHostPort,
 				"ASYNC_PROTOCOLS":    "",
 			},
 		},
 		Started: true,
 	}
 
+	// Options are all validated, so that every misconfiguration is reported at once.
+	var errs []error
 	for _, opt := range opts {
-		opt.Customize(&req)
+		if err := opt.Customize(&req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
 	}
 
 	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
 	if err != nil {
-		return nil, err
+		err = platform.Explain(ctx, req, container, err)
+		err = startup.Explain(ctx, "Microcks Async Minion container", req, err)
+		return nil, fmt.Errorf("error starting Microcks Async Minion container (%s): %w", redact.Request(req), err)
 	}
 
 	return &MicrocksAsyncMinionContainer{Container: container}, nil
 }
 
+// WithMicrocksHostPort allows to set the host and port where Microcks is reachable from the minion.
+func WithMicrocksHostPort(microcksHostPort string) testcontainers.CustomizeRequestOption {
+	return WithEnv("MICROCKS_HOST_PORT", microcksHostPort)
+}
+
 // WithNetwork allows to add a custom network.
-// Deprecated: Use network.WithNetwork from testcontainers instead.
+// Deprecated: Use WithDockerNetwork or network.WithNetwork from testcontainers instead.
 func WithNetwork(networkName string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		req.Networks = append(req.Networks, networkName)
@@ -92,8 +158,17 @@ func WithNetwork(networkName string) testcontainers.CustomizeRequestOption {
 	}
 }
 
+// WithDockerNetwork attaches the Async Minion container to given network, as created by the testcontainers network
+// package, with given aliases or DefaultNetworkAlias when none is provided.
+func WithDockerNetwork(nw *testcontainers.DockerNetwork, aliases ...string) testcontainers.CustomizeRequestOption {
+	if len(aliases) == 0 {
+		aliases = []string{DefaultNetworkAlias}
+	}
+	return network.WithNetwork(aliases, nw)
+}
+
 // WithNetworkAlias allows to add a custom network alias for a specific network.
-// Deprecated: Use network.WithNetwork from testcontainers instead.
+// Deprecated: Use WithDockerNetwork or network.WithNetwork from testcontainers instead.
 func WithNetworkAlias(networkName, networkAlias string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		if req.NetworkAliases == nil {
@@ -105,6 +180,62 @@ func WithNetworkAlias(networkName, networkAlias string) testcontainers.Customize
 	}
 }
 
+// WithMemoryLimit limits the Async Minion container memory to given bytes, without swap, so that it can be capped on
+// shared runners and is OOM-killed deterministically.
+func WithMemoryLimit(bytes int64) testcontainers.CustomizeRequestOption {
+	return resources.MemoryLimit(bytes)
+}
+
+// WithCPULimit limits the Async Minion container to given number of CPUs, e.g. 1.5.
+func WithCPULimit(cpus float64) testcontainers.CustomizeRequestOption {
+	return resources.CPULimit(cpus)
+}
+
+// WithStartupTimeout sets the time given to the Async Minion container to be ready, 60 seconds by default,
+// independently of the context given to Run. When exceeded, Run returns an error naming the container.
+func WithStartupTimeout(timeout time.Duration) testcontainers.CustomizeRequestOption {
+	return startup.Option(timeout)
+}
+
+// WithImagePlatform runs the Async Minion image for given platform, e.g. "linux/amd64" on Apple Silicon when a pinned
+// image tag lacks an arm64 build. The Docker host must then be able to emulate it.
+func WithImagePlatform(imagePlatform string) testcontainers.CustomizeRequestOption {
+	return platform.Option(imagePlatform)
+}
+
+// WithLabels adds labels to the Async Minion container, e.g. for cleanup policies or cost attribution.
+func WithLabels(labels map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		for key, value := range labels {
+			req.Labels[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithLifecycleHooks adds hooks to the Async Minion container lifecycle, e.g. to run initialization once it is started
+// or capture logs before it is terminated.
+func WithLifecycleHooks(hooks ...testcontainers.ContainerLifecycleHooks) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, hooks...)
+
+		return nil
+	}
+}
+
+// WithRequestModifier modifies the Async Minion container request with given function, e.g. to tweak settings not
+// covered by the options of the package. It is applied in order with other options.
+func WithRequestModifier(modifier func(*testcontainers.GenericContainerRequest)) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		modifier(req)
+		return nil
+	}
+}
+
 // WithEnv allows to add an environment variable.
 func WithEnv(key, value string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
@@ -117,19 +248,186 @@ func WithEnv(key, value string) testcontainers.CustomizeRequestOption {
 	}
 }
 
+// WithDebugLogging raises the Async Minion log level to DEBUG, e.g. to diagnose messages not being published.
+func WithDebugLogging() testcontainers.CustomizeRequestOption {
+	return WithEnv("QUARKUS_LOG_CATEGORY__IO_GITHUB_MICROCKS__LEVEL", "DEBUG")
+}
+
+// WithReuse names the container and re-attaches to an already running container with the same name, so that
+// repeated local test runs don't pay the minion startup time. See microcks.WithReuse.
+func WithReuse(name string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.Name = name
+		req.Reuse = true
+
+		return nil
+	}
+}
+
+// WithProxy allows to route outgoing traffic through a corporate proxy.
+func WithProxy(httpProxy, httpsProxy, noProxy string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		for key, value := range proxy.Env(httpProxy, httpsProxy, noProxy, true) {
+			req.Env[key] = value
+		}
+
+		return nil
+	}
+}
+
 // WithKafkaConnection connects the MicrocksAsyncMinionContainer to a Kafka server to allow Kafka messages mocking.
 func WithKafkaConnection(connection kafka.Connection) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
+		if connection.BootstrapServers == "" {
+			return fmt.Errorf("error configuring Kafka connection: missing bootstrap servers")
+		}
 		if req.Env == nil {
 			req.Env = make(map[string]string)
 		}
 		req.Env["KAFKA_BOOTSTRAP_SERVER"] = connection.BootstrapServers
+		if !connection.Username.IsZero() {
+			username, err := connection.Username.Resolve()
+			if err != nil {
+				return err
+			}
+			password, err := connection.Password.Resolve()
+			if err != nil {
+				return err
+			}
+			req.Env["KAFKA_SECURITY_PROTOCOL"] = "SASL_PLAINTEXT"
+			req.Env["KAFKA_SASL_MECHANISM"] = "PLAIN"
+			req.Env["KAFKA_SASL_JAAS_CONFIG"] = fmt.Sprintf(
+				"org.apache.kafka.common.security.plain.PlainLoginModule required username=%q password=%q;",
+				username,
+				password,
+			)
+		}
 		addProtocol(req, "KAFKA")
 
 		return nil
 	}
 }
 
+// WithMQTTConnection connects the MicrocksAsyncMinionContainer to a MQTT broker to allow MQTT messages mocking.
+func WithMQTTConnection(connection mqtt.Connection) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if connection.Server == "" {
+			return fmt.Errorf("error configuring MQTT connection: missing server")
+		}
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		req.Env["MQTT_SERVER"] = connection.Server
+		if !connection.Username.IsZero() {
+			username, err := connection.Username.Resolve()
+			if err != nil {
+				return err
+			}
+			password, err := connection.Password.Resolve()
+			if err != nil {
+				return err
+			}
+			req.Env["MQTT_USERNAME"] = username
+			req.Env["MQTT_PASSWORD"] = password
+		}
+		addProtocol(req, "MQTT")
+
+		return nil
+	}
+}
+
+// WithAMQPConnection connects the MicrocksAsyncMinionContainer to an AMQP broker to allow AMQP messages mocking.
+func WithAMQPConnection(connection amqp.Connection) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if connection.Server == "" {
+			return fmt.Errorf("error configuring AMQP connection: missing server")
+		}
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		req.Env["AMQP_SERVER"] = connection.Server
+		if !connection.Username.IsZero() {
+			username, err := connection.Username.Resolve()
+			if err != nil {
+				return err
+			}
+			password, err := connection.Password.Resolve()
+			if err != nil {
+				return err
+			}
+			req.Env["AMQP_USERNAME"] = username
+			req.Env["AMQP_PASSWORD"] = password
+		}
+		addProtocol(req, "AMQP")
+
+		return nil
+	}
+}
+
+// WithAmazonSQSConnection connects the MicrocksAsyncMinionContainer to Amazon SQS to allow SQS messages mocking.
+func WithAmazonSQSConnection(connection amazonservice.Connection) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if connection.Region == "" {
+			return fmt.Errorf("error configuring Amazon SQS connection: missing region")
+		}
+		if err := addAmazonCredentials(req, connection); err != nil {
+			return err
+		}
+		req.Env["AWS_SQS_REGION"] = connection.Region
+		if connection.EndpointOverride != "" {
+			req.Env["AWS_SQS_ENDPOINT"] = connection.EndpointOverride
+		}
+		addProtocol(req, "SQS")
+
+		return nil
+	}
+}
+
+// WithAmazonSNSConnection connects the MicrocksAsyncMinionContainer to Amazon SNS to allow SNS messages mocking.
+func WithAmazonSNSConnection(connection amazonservice.Connection) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if connection.Region == "" {
+			return fmt.Errorf("error configuring Amazon SNS connection: missing region")
+		}
+		if err := addAmazonCredentials(req, connection); err != nil {
+			return err
+		}
+		req.Env["AWS_SNS_REGION"] = connection.Region
+		if connection.EndpointOverride != "" {
+			req.Env["AWS_SNS_ENDPOINT"] = connection.EndpointOverride
+		}
+		addProtocol(req, "SNS")
+
+		return nil
+	}
+}
+
+// WithGooglePubSubConnection connects the MicrocksAsyncMinionContainer to Google Cloud Pub/Sub to allow
+// Pub/Sub messages mocking.
+func WithGooglePubSubConnection(connection googlepubsub.Connection) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if connection.Project == "" {
+			return fmt.Errorf("error configuring Google Cloud Pub/Sub connection: missing project")
+		}
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		req.Env["GOOGLEPUBSUB_PROJECT"] = connection.Project
+		if connection.ServiceAccountLocation != "" {
+			req.Env["GOOGLEPUBSUB_SERVICE_ACCOUNT_LOCATION"] = connection.ServiceAccountLocation
+		}
+		if connection.EmulatorHost != "" {
+			req.Env["PUBSUB_EMULATOR_HOST"] = connection.EmulatorHost
+		}
+		addProtocol(req, "GOOGLEPUBSUB")
+
+		return nil
+	}
+}
+
 // WSMockEndpoint gets the exposed mock endpoints for a WebSocket Service.
 func (container *MicrocksAsyncMinionContainer) WSMockEndpoint(ctx context.Context, service, version, operationName string) (string, error) {
 	// Get the container host.
@@ -157,9 +455,8 @@ func (container *MicrocksAsyncMinionContainer) WSMockEndpoint(ctx context.Contex
 	}
 
 	return fmt.Sprintf(
-		"ws://%s:%s/api/ws/%s/%s/%s",
-		host,
-		port,
+		"ws://%s/api/ws/%s/%s/%s",
+		net.JoinHostPort(host, port),
 		service,
 		version,
 		operationName,
@@ -181,6 +478,190 @@ func (container *MicrocksAsyncMinionContainer) KafkaMockTopic(service, version,
 	return fmt.Sprintf("%s-%s-%s", service, version, operationName)
 }
 
+// MQTTMockTopic gets the exposed mock topic for a MQTT Service.
+func (container *MicrocksAsyncMinionContainer) MQTTMockTopic(service, version, operationName string) string {
+	// Format operationName.
+	if strings.Index(operationName, " ") != -1 {
+		operationName = strings.Split(operationName, " ")[1]
+	}
+
+	// Format service.
+	r := strings.NewReplacer(" ", "", "-", "")
+	service = r.Replace(service)
+
+	return fmt.Sprintf("%s-%s-%s", service, version, operationName)
+}
+
+// SQSMockQueue gets the exposed mock queue for a SQS Service.
+func (container *MicrocksAsyncMinionContainer) SQSMockQueue(service, version, operationName string) string {
+	return container.KafkaMockTopic(service, version, operationName)
+}
+
+// SNSMockTopic gets the exposed mock topic for a SNS Service.
+func (container *MicrocksAsyncMinionContainer) SNSMockTopic(service, version, operationName string) string {
+	return container.KafkaMockTopic(service, version, operationName)
+}
+
+// GooglePubSubMockTopic gets the exposed mock topic for a Google Cloud Pub/Sub Service.
+func (container *MicrocksAsyncMinionContainer) GooglePubSubMockTopic(service, version, operationName string) string {
+	return container.KafkaMockTopic(service, version, operationName)
+}
+
+// AMQPMockDestination gets the exposed mock destination for an AMQP Service.
+func (container *MicrocksAsyncMinionContainer) AMQPMockDestination(service, version, operationName string) string {
+	return container.MQTTMockTopic(service, version, operationName)
+}
+
+// PublishedMessagesCount gets the number of mock messages published by the minion for an operation of a Service.
+// It is read from the minion metrics endpoint, so that tests can check that event mocking is active before consuming.
+func (container *MicrocksAsyncMinionContainer) PublishedMessagesCount(ctx context.Context, service, version, operationName string) (int, error) {
+	families, err := container.Metrics(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	family, ok := families[publishedMessagesMetric]
+	if !ok {
+		return 0, nil
+	}
+
+	return int(family.Sum("service", service, "version", version, "operation", operationName)), nil
+}
+
+// ExportMetrics writes the minion Prometheus metrics relevant to mocks to w, in the Prometheus text format,
+// so that they can be pushed to a Pushgateway.
+func (container *MicrocksAsyncMinionContainer) ExportMetrics(ctx context.Context, w io.Writer) error {
+	families, err := container.Metrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Keep minion own metrics only.
+	relevant := metrics.Filter(families, func(f *metrics.Family, s metrics.Sample) bool {
+		return strings.HasPrefix(f.Name, "microcks_")
+	})
+
+	return metrics.Write(w, relevant)
+}
+
+// FollowLogs streams the Async Minion container logs to w, from its start and until ctx is done, e.g. to watch a
+// flaky asynchronous test live. It blocks, so it is usually run in its own goroutine.
+func (container *MicrocksAsyncMinionContainer) FollowLogs(ctx context.Context, w io.Writer) error {
+	return logs.Follow(ctx, container.GetContainerID(), w)
+}
+
+// Health gets the Async Minion health status, reported by its "/q/health" endpoint with a status per check,
+// e.g. the connection to a broker.
+func (container *MicrocksAsyncMinionContainer) Health(ctx context.Context) (*health.Status, error) {
+	endpoint, err := container.httpEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return health.Get(ctx, http.DefaultClient, endpoint+HealthPath)
+}
+
+// IsReady tells if the Async Minion is up, e.g. for a sanity check in the middle of a test.
+func (container *MicrocksAsyncMinionContainer) IsReady(ctx context.Context) bool {
+	status, err := container.Health(ctx)
+	return err == nil && status.IsUp()
+}
+
+// ProtocolStats represents the mock messages published by the minion over a protocol.
+type ProtocolStats struct {
+	// Published represents the number of published messages.
+	Published int
+
+	// Errors represents the number of messages that failed to be published, e.g. on a broken broker connection.
+	Errors int
+}
+
+// PublishStats gets the published messages and publishing errors of the minion, by protocol (e.g. "kafka"), so that
+// tests can detect a silently failing broker connection instead of timing out waiting for messages.
+func (container *MicrocksAsyncMinionContainer) PublishStats(ctx context.Context) (map[string]ProtocolStats, error) {
+	families, err := container.Metrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return publishStats(families), nil
+}
+
+func publishStats(families map[string]*metrics.Family) map[string]ProtocolStats {
+	stats := make(map[string]ProtocolStats)
+	if family, ok := families[publishedMessagesMetric]; ok {
+		for protocol, published := range family.SumBy(protocolLabel) {
+			protocolStats := stats[protocol]
+			protocolStats.Published = int(published)
+			stats[protocol] = protocolStats
+		}
+	}
+	if family, ok := families[publishErrorsMetric]; ok {
+		for protocol, errors := range family.SumBy(protocolLabel) {
+			protocolStats := stats[protocol]
+			protocolStats.Errors = int(errors)
+			stats[protocol] = protocolStats
+		}
+	}
+
+	return stats
+}
+
+// Metrics scrapes the minion Prometheus metrics and returns them by family name.
+func (container *MicrocksAsyncMinionContainer) Metrics(ctx context.Context) (map[string]*metrics.Family, error) {
+	endpoint, err := container.MetricsEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics.Scrape(ctx, endpoint)
+}
+
+// MetricsEndpoint gets the URL of the minion Prometheus metrics endpoint, "/q/metrics".
+func (container *MicrocksAsyncMinionContainer) MetricsEndpoint(ctx context.Context) (string, error) {
+	endpoint, err := container.httpEndpoint(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return endpoint + "/q/metrics", nil
+}
+
+func (container *MicrocksAsyncMinionContainer) httpEndpoint(ctx context.Context) (string, error) {
+	host, err := container.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := container.MappedPort(ctx, DefaultHttpPort)
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + net.JoinHostPort(host, port.Port()), nil
+}
+
+func addAmazonCredentials(req *testcontainers.GenericContainerRequest, connection amazonservice.Connection) error {
+	if req.Env == nil {
+		req.Env = make(map[string]string)
+	}
+	if connection.AccessKey.IsZero() {
+		return nil
+	}
+
+	accessKey, err := connection.AccessKey.Resolve()
+	if err != nil {
+		return err
+	}
+	secretKey, err := connection.SecretKey.Resolve()
+	if err != nil {
+		return err
+	}
+	req.Env["AWS_ACCESS_KEY_ID"] = accessKey
+	req.Env["AWS_SECRET_ACCESS_KEY"] = secretKey
+	return nil
+}
+
 func addProtocol(req *testcontainers.GenericContainerRequest, protocol string) {
 	if _, ok := req.Env["ASYNC_PROTOCOLS"]; !ok {
 		req.Env["ASYNC_PROTOCOLS"] = ""
@@ -15,6 +15,8 @@
  */
 package amazonservice
 
+import "microcks.io/testcontainers-go/ensemble/async/connection"
+
 // Connection represents an Amazon Service connection settings.
 type Connection struct {
 	// Region represents a region.
@@ -24,8 +26,8 @@ type Connection struct {
 	EndpointOverride string
 
 	// AccessKey represents an access key.
-	accessKey string
+	AccessKey connection.Credential
 
-	// SccessKey represents a secret key.
-	SecretKey string
+	// SecretKey represents a secret key.
+	SecretKey connection.Credential
 }
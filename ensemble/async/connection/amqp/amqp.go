@@ -0,0 +1,30 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package amqp
+
+import "microcks.io/testcontainers-go/ensemble/async/connection"
+
+// Connection represents AMQP broker connection settings.
+type Connection struct {
+	// Server represents the broker host and port, e.g. "rabbitmq:5672".
+	Server string
+
+	// Username represents the username (optional).
+	Username connection.Credential
+
+	// Password represents the password (optional).
+	Password connection.Credential
+}
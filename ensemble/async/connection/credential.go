@@ -0,0 +1,83 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package connection
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// redacted represents the text printed in place of a credential value.
+const redacted = "[REDACTED]"
+
+// Credential represents a broker credential, given as a value or read from an environment variable
+// or a file when the container starts. Its value is never printed.
+type Credential struct {
+	value string
+	env   string
+	file  string
+}
+
+// FromValue creates a credential from the given value.
+func FromValue(value string) Credential {
+	return Credential{value: value}
+}
+
+// FromEnv creates a credential read from the given environment variable.
+func FromEnv(key string) Credential {
+	return Credential{env: key}
+}
+
+// FromFile creates a credential read from the given file, trailing new lines being removed.
+func FromFile(path string) Credential {
+	return Credential{file: path}
+}
+
+// IsZero reports whether the credential has not been set.
+func (c Credential) IsZero() bool {
+	return c == Credential{}
+}
+
+// Resolve returns the credential value.
+func (c Credential) Resolve() (string, error) {
+	switch {
+	case c.env != "":
+		value, ok := os.LookupEnv(c.env)
+		if !ok {
+			return "", fmt.Errorf("credential environment variable %s is not set", c.env)
+		}
+		return value, nil
+	case c.file != "":
+		content, err := os.ReadFile(c.file)
+		if err != nil {
+			return "", fmt.Errorf("error reading credential file: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	default:
+		return c.value, nil
+	}
+}
+
+// String implements fmt.Stringer, never printing the credential value.
+func (c Credential) String() string {
+	return redacted
+}
+
+// GoString implements fmt.GoStringer, never printing the credential value.
+func (c Credential) GoString() string {
+	return redacted
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package connection_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/testcontainers-go/ensemble/async/connection"
+)
+
+func TestCredentialResolve(t *testing.T) {
+	value, err := connection.FromValue("s3cr3t").Resolve()
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", value)
+
+	t.Setenv("BROKER_PASSWORD", "from-env")
+	value, err = connection.FromEnv("BROKER_PASSWORD").Resolve()
+	require.NoError(t, err)
+	require.Equal(t, "from-env", value)
+
+	_, err = connection.FromEnv("BROKER_PASSWORD_UNSET").Resolve()
+	require.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0o600))
+	value, err = connection.FromFile(file).Resolve()
+	require.NoError(t, err)
+	require.Equal(t, "from-file", value)
+}
+
+func TestCredentialNeverPrinted(t *testing.T) {
+	c := connection.FromValue("s3cr3t")
+	require.False(t, c.IsZero())
+	require.True(t, connection.Credential{}.IsZero())
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v"} {
+		require.NotContains(t, fmt.Sprintf(format, c), "s3cr3t")
+		require.NotContains(t, fmt.Sprintf(format, struct{ Password connection.Credential }{c}), "s3cr3t")
+	}
+}
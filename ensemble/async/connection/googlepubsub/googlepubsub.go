@@ -0,0 +1,28 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package googlepubsub
+
+// Connection represents Google Cloud Pub/Sub connection settings.
+type Connection struct {
+	// Project represents the Google Cloud project identifier.
+	Project string
+
+	// ServiceAccountLocation represents the path of the service account JSON key within the minion container (optional).
+	ServiceAccountLocation string
+
+	// EmulatorHost represents the host and port of a Pub/Sub emulator, e.g. "pubsub:8085" (optional).
+	EmulatorHost string
+}
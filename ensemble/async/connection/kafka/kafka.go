@@ -15,8 +15,16 @@
  */
 package kafka
 
+import "microcks.io/testcontainers-go/ensemble/async/connection"
+
 // Connection represents broker connection settings.
 type Connection struct {
 	// BootstrapServers represents the list of bootstrap servers.
 	BootstrapServers string
+
+	// Username represents the SASL PLAIN username (optional).
+	Username connection.Credential
+
+	// Password represents the SASL PLAIN password (optional).
+	Password connection.Credential
 }
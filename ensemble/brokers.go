@@ -0,0 +1,156 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/redact"
+	"microcks.io/testcontainers-go/internal/registry"
+)
+
+const (
+	defaultMQTTImage         = "eclipse-mosquitto:2.0"
+	defaultAMQPImage         = "rabbitmq:3.13-alpine"
+	defaultGooglePubSubImage = "gcr.io/google.com/cloudsdktool/google-cloud-cli:480.0.0-emulators"
+
+	// mosquittoConfig allows anonymous connections to the MQTT broker.
+	mosquittoConfig = "listener 1883\nallow_anonymous true\n"
+)
+
+// runMQTTBroker starts a Mosquitto MQTT broker accepting anonymous connections.
+func runMQTTBroker(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (testcontainers.Container, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        defaultMQTTImage,
+			ExposedPorts: []string{MQTTBrokerPort},
+			Files: []testcontainers.ContainerFile{
+				{
+					Reader:            strings.NewReader(mosquittoConfig),
+					ContainerFilePath: "/mosquitto/config/mosquitto.conf",
+					FileMode:          0o644,
+				},
+			},
+			WaitingFor: wait.ForLog("mosquitto version"),
+		},
+		Started: true,
+	}
+
+	for _, opt := range opts {
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error starting MQTT broker container (%s): %w", redact.Request(req), err)
+	}
+
+	return container, nil
+}
+
+// runAMQPBroker starts a RabbitMQ AMQP broker with a user for the Async Minion, as the default guest one
+// is restricted to local connections.
+func runAMQPBroker(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (testcontainers.Container, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        defaultAMQPImage,
+			ExposedPorts: []string{AMQPBrokerPort},
+			Env: map[string]string{
+				"RABBITMQ_DEFAULT_USER":  AMQPUsername,
+				"RABBITMQ_DEFAULT_PASS":  AMQPPassword,
+				"RABBITMQ_DEFAULT_VHOST": "/",
+			},
+			WaitingFor: wait.ForLog("Server startup complete"),
+		},
+		Started: true,
+	}
+
+	for _, opt := range opts {
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error starting AMQP broker container (%s): %w", redact.Request(req), err)
+	}
+
+	return container, nil
+}
+
+// runGooglePubSubEmulator starts the Google Cloud Pub/Sub emulator for given project.
+func runGooglePubSubEmulator(ctx context.Context, project string, opts ...testcontainers.ContainerCustomizer) (testcontainers.Container, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        defaultGooglePubSubImage,
+			ExposedPorts: []string{GooglePubSubPort},
+			Cmd: []string{
+				"/bin/sh", "-c",
+				"gcloud beta emulators pubsub start --host-port 0.0.0.0:8085 --project=" + project,
+			},
+			WaitingFor: wait.ForLog("started"),
+		},
+		Started: true,
+	}
+
+	for _, opt := range opts {
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error starting Pub/Sub emulator container (%s): %w", redact.Request(req), err)
+	}
+
+	return container, nil
+}
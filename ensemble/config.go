@@ -0,0 +1,212 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	"microcks.io/go-client"
+)
+
+// Config represents a declarative definition of an ensemble, that can be committed and shared between local
+// tests and CI. It can be unmarshaled from YAML or JSON.
+type Config struct {
+	// Microcks represents the Microcks member settings.
+	Microcks MemberConfig `json:"microcks" yaml:"microcks"`
+	// Postman represents the Postman member settings.
+	Postman MemberConfig `json:"postman" yaml:"postman"`
+	// Async represents the Async Feature settings.
+	Async AsyncConfig `json:"async" yaml:"async"`
+	// Keycloak represents the Keycloak member settings.
+	Keycloak KeycloakConfig `json:"keycloak" yaml:"keycloak"`
+	// Brokers represents the brokers started by the ensemble.
+	Brokers BrokersConfig `json:"brokers" yaml:"brokers"`
+	// Artifacts represents the artifacts imported within Microcks.
+	Artifacts ArtifactsConfig `json:"artifacts" yaml:"artifacts"`
+	// Secrets represents the secrets created within Microcks.
+	Secrets []SecretConfig `json:"secrets" yaml:"secrets"`
+}
+
+// MemberConfig represents the settings of an ensemble member.
+type MemberConfig struct {
+	// Enabled tells if the member is started. It is ignored for Microcks, which is always started.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Image represents the member image, the default one when empty.
+	Image string `json:"image" yaml:"image"`
+}
+
+// AsyncConfig represents the Async Feature settings.
+type AsyncConfig struct {
+	MemberConfig `yaml:",inline"`
+	// DefaultFrequency represents the mock messages publication frequency, in seconds, the default one when zero.
+	DefaultFrequency int `json:"defaultFrequency" yaml:"defaultFrequency"`
+}
+
+// KeycloakConfig represents the Keycloak member settings.
+type KeycloakConfig struct {
+	// Enabled tells if Keycloak is started.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// RealmFile represents the realm export to import, the default Microcks realm when empty.
+	RealmFile string `json:"realmFile" yaml:"realmFile"`
+}
+
+// BrokersConfig represents the brokers started by the ensemble, which enable the Async Feature.
+type BrokersConfig struct {
+	Kafka               bool     `json:"kafka" yaml:"kafka"`
+	MQTT                bool     `json:"mqtt" yaml:"mqtt"`
+	AMQP                bool     `json:"amqp" yaml:"amqp"`
+	LocalStack          []string `json:"localStack" yaml:"localStack"`
+	GooglePubSubProject string   `json:"googlePubSubProject" yaml:"googlePubSubProject"`
+}
+
+// ArtifactsConfig represents the artifacts imported within Microcks.
+type ArtifactsConfig struct {
+	Main            []string `json:"main" yaml:"main"`
+	Secondary       []string `json:"secondary" yaml:"secondary"`
+	RemoteMain      []string `json:"remoteMain" yaml:"remoteMain"`
+	RemoteSecondary []string `json:"remoteSecondary" yaml:"remoteSecondary"`
+	Snapshots       []string `json:"snapshots" yaml:"snapshots"`
+}
+
+// SecretConfig represents a secret created within Microcks.
+type SecretConfig struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Username    string `json:"username" yaml:"username"`
+	Password    string `json:"password" yaml:"password"`
+	Token       string `json:"token" yaml:"token"`
+	TokenHeader string `json:"tokenHeader" yaml:"tokenHeader"`
+	// CACertFile represents the path of a PEM encoded CA certificate file.
+	CACertFile string `json:"caCertFile" yaml:"caCertFile"`
+}
+
+// LoadConfig reads an ensemble configuration from a YAML or JSON file. Environment variables references,
+// like ${REPO_TOKEN}, are expanded so that credentials don't have to be committed.
+func LoadConfig(configFile string) (*Config, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ensemble configuration: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &config); err != nil {
+		return nil, fmt.Errorf("error decoding ensemble configuration: %w", err)
+	}
+	return &config, nil
+}
+
+// WithConfig configures the ensemble from a declarative configuration.
+// Options given after it override its settings.
+func WithConfig(config Config) Option {
+	var opts []Option
+
+	if config.Microcks.Image != "" {
+		opts = append(opts, WithMicrocksImage(config.Microcks.Image))
+	}
+	if config.Postman.Enabled {
+		opts = append(opts, WithPostman(true))
+		if config.Postman.Image != "" {
+			opts = append(opts, WithPostmanImage(config.Postman.Image))
+		}
+	}
+	if config.Async.Enabled {
+		opts = append(opts, WithAsyncFeature())
+		if config.Async.Image != "" {
+			opts = append(opts, WithAsyncFeatureImage(config.Async.Image))
+		}
+	}
+	if config.Async.DefaultFrequency > 0 {
+		opts = append(opts, WithAsyncDefaultFrequency(config.Async.DefaultFrequency))
+	}
+	if config.Keycloak.Enabled {
+		if config.Keycloak.RealmFile != "" {
+			opts = append(opts, WithKeycloak(config.Keycloak.RealmFile))
+		} else {
+			opts = append(opts, WithKeycloakFeature())
+		}
+	}
+
+	if config.Brokers.Kafka {
+		opts = append(opts, WithKafka())
+	}
+	if config.Brokers.MQTT {
+		opts = append(opts, WithMQTTBroker())
+	}
+	if config.Brokers.AMQP {
+		opts = append(opts, WithAMQPBroker())
+	}
+	if len(config.Brokers.LocalStack) > 0 {
+		opts = append(opts, WithLocalStack(config.Brokers.LocalStack...))
+	}
+	if config.Brokers.GooglePubSubProject != "" {
+		opts = append(opts, WithGooglePubSubEmulator(config.Brokers.GooglePubSubProject))
+	}
+
+	for _, s := range config.Secrets {
+		secret, err := s.secret()
+		if err != nil {
+			return func(e *MicrocksContainersEnsemble) error { return err }
+		}
+		opts = append(opts, WithSecret(secret))
+	}
+
+	for _, path := range config.Artifacts.Main {
+		opts = append(opts, WithMainArtifact(path))
+	}
+	for _, path := range config.Artifacts.Secondary {
+		opts = append(opts, WithSecondaryArtifact(path))
+	}
+	for _, remoteURL := range config.Artifacts.RemoteMain {
+		opts = append(opts, WithMainRemoteArtifact(remoteURL))
+	}
+	for _, remoteURL := range config.Artifacts.RemoteSecondary {
+		opts = append(opts, WithSecondaryRemoteArtifact(remoteURL))
+	}
+	if len(config.Artifacts.Snapshots) > 0 {
+		opts = append(opts, WithSnapshots(config.Artifacts.Snapshots...))
+	}
+
+	return withPreset(opts...)
+}
+
+// secret converts the configuration to a Microcks secret.
+func (s SecretConfig) secret() (client.Secret, error) {
+	secret := client.Secret{
+		Name:        s.Name,
+		Description: s.Description,
+		Username:    optional(s.Username),
+		Password:    optional(s.Password),
+		Token:       optional(s.Token),
+		TokenHeader: optional(s.TokenHeader),
+	}
+	if s.CACertFile != "" {
+		caCert, err := os.ReadFile(s.CACertFile)
+		if err != nil {
+			return client.Secret{}, fmt.Errorf("error reading CA certificate of secret %s: %w", s.Name, err)
+		}
+		secret.CaCertPem = optional(string(caCert))
+	}
+	return secret, nil
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
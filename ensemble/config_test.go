@@ -0,0 +1,63 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/testcontainers-go/ensemble"
+	"microcks.io/testcontainers-go/internal/test"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MICROCKS_TEST_REPOSITORY_TOKEN", "my-token")
+
+	config, err := ensemble.LoadConfig("../testdata/ensemble.yaml")
+	require.NoError(t, err)
+
+	require.Equal(t, "quay.io/microcks/microcks-uber:1.9.1", config.Microcks.Image)
+	require.True(t, config.Async.Enabled)
+	require.Equal(t, 3, config.Async.DefaultFrequency)
+	require.True(t, config.Brokers.Kafka)
+	require.Len(t, config.Artifacts.Main, 2)
+	require.Len(t, config.Secrets, 1)
+	require.Equal(t, "my-token", config.Secrets[0].Token)
+}
+
+func TestConfigFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	config, err := ensemble.LoadConfig("../testdata/ensemble.yaml")
+	require.NoError(t, err)
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(ctx, ensemble.WithConfig(*config))
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+	test.MockEndpoints(t, ctx, ec.GetMicrocksContainer())
+	require.NotNil(t, ec.GetKafkaContainer())
+}
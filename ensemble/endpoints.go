@@ -0,0 +1,88 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"context"
+	"errors"
+)
+
+// errAsyncFeatureDisabled is returned by async endpoints helpers when the Async Feature is not enabled.
+var errAsyncFeatureDisabled = errors.New("async feature is not enabled")
+
+// HttpEndpoint gets the exposed Microcks HTTP endpoint.
+func (ec *MicrocksContainersEnsemble) HttpEndpoint(ctx context.Context) (string, error) {
+	return ec.microcksContainer.HttpEndpoint(ctx)
+}
+
+// SoapMockEndpoint gets the exposed mock endpoint for a SOAP Service.
+func (ec *MicrocksContainersEnsemble) SoapMockEndpoint(ctx context.Context, service, version string) (string, error) {
+	return ec.microcksContainer.SoapMockEndpoint(ctx, service, version)
+}
+
+// RestMockEndpoint gets the exposed mock endpoint for a REST API.
+func (ec *MicrocksContainersEnsemble) RestMockEndpoint(ctx context.Context, service, version string) (string, error) {
+	return ec.microcksContainer.RestMockEndpoint(ctx, service, version)
+}
+
+// GraphQLMockEndpoint gets the exposed mock endpoint for a GraphQL API.
+func (ec *MicrocksContainersEnsemble) GraphQLMockEndpoint(ctx context.Context, service, version string) (string, error) {
+	return ec.microcksContainer.GraphQLMockEndpoint(ctx, service, version)
+}
+
+// GrpcMockEndpoint gets the exposed mock endpoint for a GRPC Service.
+func (ec *MicrocksContainersEnsemble) GrpcMockEndpoint(ctx context.Context) (string, error) {
+	return ec.microcksContainer.GrpcMockEndpoint(ctx)
+}
+
+// WSMockEndpoint gets the exposed mock endpoint for a WebSocket Service.
+// It fails when the Async Feature is not enabled.
+func (ec *MicrocksContainersEnsemble) WSMockEndpoint(ctx context.Context, service, version, operationName string) (string, error) {
+	if ec.asyncMinionContainer == nil {
+		return "", errAsyncFeatureDisabled
+	}
+	return ec.asyncMinionContainer.WSMockEndpoint(ctx, service, version, operationName)
+}
+
+// KafkaMockTopic gets the exposed mock topic for a Kafka Service.
+func (ec *MicrocksContainersEnsemble) KafkaMockTopic(service, version, operationName string) string {
+	return ec.asyncMinionContainer.KafkaMockTopic(service, version, operationName)
+}
+
+// MQTTMockTopic gets the exposed mock topic for a MQTT Service.
+func (ec *MicrocksContainersEnsemble) MQTTMockTopic(service, version, operationName string) string {
+	return ec.asyncMinionContainer.MQTTMockTopic(service, version, operationName)
+}
+
+// AMQPMockDestination gets the exposed mock destination for an AMQP Service.
+func (ec *MicrocksContainersEnsemble) AMQPMockDestination(service, version, operationName string) string {
+	return ec.asyncMinionContainer.AMQPMockDestination(service, version, operationName)
+}
+
+// SQSMockQueue gets the exposed mock queue for a SQS Service.
+func (ec *MicrocksContainersEnsemble) SQSMockQueue(service, version, operationName string) string {
+	return ec.asyncMinionContainer.SQSMockQueue(service, version, operationName)
+}
+
+// SNSMockTopic gets the exposed mock topic for a SNS Service.
+func (ec *MicrocksContainersEnsemble) SNSMockTopic(service, version, operationName string) string {
+	return ec.asyncMinionContainer.SNSMockTopic(service, version, operationName)
+}
+
+// GooglePubSubMockTopic gets the exposed mock topic for a Google Cloud Pub/Sub Service.
+func (ec *MicrocksContainersEnsemble) GooglePubSubMockTopic(service, version, operationName string) string {
+	return ec.asyncMinionContainer.GooglePubSubMockTopic(service, version, operationName)
+}
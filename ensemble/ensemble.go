@@ -17,15 +17,59 @@ package ensemble
 
 import (
 	"context"
+	"errors"
+	"net"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/docker/go-connections/nat"
 	"github.com/testcontainers/testcontainers-go"
+	kafkaTC "github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/testcontainers/testcontainers-go/network"
 	"microcks.io/go-client"
 	microcks "microcks.io/testcontainers-go"
 	"microcks.io/testcontainers-go/ensemble/async"
+	"microcks.io/testcontainers-go/ensemble/async/connection"
+	"microcks.io/testcontainers-go/ensemble/async/connection/amazonservice"
+	"microcks.io/testcontainers-go/ensemble/async/connection/amqp"
+	"microcks.io/testcontainers-go/ensemble/async/connection/googlepubsub"
 	"microcks.io/testcontainers-go/ensemble/async/connection/kafka"
+	"microcks.io/testcontainers-go/ensemble/async/connection/mqtt"
+	"microcks.io/testcontainers-go/ensemble/keycloak"
+	"microcks.io/testcontainers-go/ensemble/localstack"
 	"microcks.io/testcontainers-go/ensemble/postman"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/registry"
+)
+
+const (
+	// NetworkLabel is the label set on networks created by the ensemble.
+	NetworkLabel = "io.microcks.testcontainers.ensemble"
+
+	// KafkaNetworkAlias represents the network alias of the Kafka broker started by the ensemble.
+	KafkaNetworkAlias = "kafka"
+	// KafkaBrokerPort represents the Kafka broker port reachable from the ensemble network.
+	KafkaBrokerPort = "9092"
+
+	// MQTTNetworkAlias represents the network alias of the MQTT broker started by the ensemble.
+	MQTTNetworkAlias = "mqtt"
+	// MQTTBrokerPort represents the MQTT broker port.
+	MQTTBrokerPort = "1883/tcp"
+
+	// GooglePubSubNetworkAlias represents the network alias of the Pub/Sub emulator started by the ensemble.
+	GooglePubSubNetworkAlias = "pubsub"
+	// GooglePubSubPort represents the Pub/Sub emulator port.
+	GooglePubSubPort = "8085/tcp"
+
+	// AMQPNetworkAlias represents the network alias of the AMQP broker started by the ensemble.
+	AMQPNetworkAlias = "rabbitmq"
+	// AMQPBrokerPort represents the AMQP broker port.
+	AMQPBrokerPort = "5672/tcp"
+	// AMQPUsername represents the user created on the AMQP broker started by the ensemble.
+	AMQPUsername = "microcks"
+	// AMQPPassword represents the password of the user created on the AMQP broker started by the ensemble.
+	AMQPPassword = "microcks"
 )
 
 // Option represents an option to pass to the ensemble.
@@ -45,20 +89,59 @@ func (co *ContainerOptions) Add(opt testcontainers.ContainerCustomizer) {
 type MicrocksContainersEnsemble struct {
 	ctx context.Context
 
-	network *testcontainers.DockerNetwork
+	network      *testcontainers.DockerNetwork
+	ownedNetwork bool
 
 	hostAccessPorts []int
+	labels          map[string]string
+
+	app          *appContainer
+	appContainer testcontainers.Container
+
+	sequentialStartup    bool
+	staticHostResolution bool
+	reuseName            string
+	readinessTimeout     time.Duration
 
 	microcksContainer        *microcks.MicrocksContainer
 	microcksContainerOptions ContainerOptions
+	remoteMicrocksEndpoint   string
 
 	postmanEnabled          bool
+	postmanCustomized       bool
 	postmanContainer        *postman.PostmanContainer
 	postmanContainerOptions ContainerOptions
 
 	asyncEnabled                bool
+	minionCustomized            bool
 	asyncMinionContainer        *async.MicrocksAsyncMinionContainer
 	asyncMinionContainerOptions ContainerOptions
+
+	keycloakEnabled          bool
+	keycloakCustomized       bool
+	keycloakRealm            *keycloak.Realm
+	keycloakContainer        *keycloak.KeycloakContainer
+	keycloakContainerOptions ContainerOptions
+
+	kafkaEnabled          bool
+	kafkaContainer        *kafkaTC.KafkaContainer
+	kafkaContainerOptions ContainerOptions
+
+	mqttEnabled          bool
+	mqttContainer        testcontainers.Container
+	mqttContainerOptions ContainerOptions
+
+	amqpEnabled          bool
+	amqpContainer        testcontainers.Container
+	amqpContainerOptions ContainerOptions
+
+	localStackServices         []string
+	localStackContainer        *localstack.LocalStackContainer
+	localStackContainerOptions ContainerOptions
+
+	googlePubSubProject          string
+	googlePubSubContainer        testcontainers.Container
+	googlePubSubContainerOptions ContainerOptions
 }
 
 // GetNetwork returns the ensemble network.
@@ -71,64 +154,172 @@ func (ec *MicrocksContainersEnsemble) GetMicrocksContainer() *microcks.MicrocksC
 	return ec.microcksContainer
 }
 
-// GetPostmanContainer returns the Postman container.
+// GetPostmanContainer returns the Postman container, or nil when Postman is not enabled.
 func (ec *MicrocksContainersEnsemble) GetPostmanContainer() *postman.PostmanContainer {
 	return ec.postmanContainer
 }
 
-// GetAsyncMinionContainer returns the Async Minion container.
+// GetAsyncMinionContainer returns the Async Minion container, or nil when the Async Feature is not enabled.
 func (ec *MicrocksContainersEnsemble) GetAsyncMinionContainer() *async.MicrocksAsyncMinionContainer {
 	return ec.asyncMinionContainer
 }
 
-// Terminate helps to terminate all containers.
+// GetKeycloakContainer returns the Keycloak container, or nil when the Keycloak Feature is not enabled.
+func (ec *MicrocksContainersEnsemble) GetKeycloakContainer() *keycloak.KeycloakContainer {
+	return ec.keycloakContainer
+}
+
+// GetKafkaContainer returns the Kafka container, or nil when Kafka is not enabled.
+func (ec *MicrocksContainersEnsemble) GetKafkaContainer() *kafkaTC.KafkaContainer {
+	return ec.kafkaContainer
+}
+
+// GetMQTTBrokerContainer returns the MQTT broker container, or nil when the MQTT broker is not enabled.
+func (ec *MicrocksContainersEnsemble) GetMQTTBrokerContainer() testcontainers.Container {
+	return ec.mqttContainer
+}
+
+// GetAMQPBrokerContainer returns the AMQP broker container, or nil when the AMQP broker is not enabled.
+func (ec *MicrocksContainersEnsemble) GetAMQPBrokerContainer() testcontainers.Container {
+	return ec.amqpContainer
+}
+
+// GetLocalStackContainer returns the LocalStack container, or nil when LocalStack is not enabled.
+func (ec *MicrocksContainersEnsemble) GetLocalStackContainer() *localstack.LocalStackContainer {
+	return ec.localStackContainer
+}
+
+// GetGooglePubSubEmulatorContainer returns the Pub/Sub emulator container, or nil when it is not enabled.
+func (ec *MicrocksContainersEnsemble) GetGooglePubSubEmulatorContainer() testcontainers.Container {
+	return ec.googlePubSubContainer
+}
+
+// GooglePubSubEmulatorEndpoint returns the host and port of the Pub/Sub emulator, to be used as
+// PUBSUB_EMULATOR_HOST by the application under test.
+func (ec *MicrocksContainersEnsemble) GooglePubSubEmulatorEndpoint(ctx context.Context) (string, error) {
+	host, err := ec.googlePubSubContainer.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := ec.googlePubSubContainer.MappedPort(ctx, GooglePubSubPort)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, port.Port()), nil
+}
+
+// Terminate helps to terminate all containers and the network created by the ensemble.
+// Members are terminated in reverse dependency order, errors are aggregated.
 func (ec *MicrocksContainersEnsemble) Terminate(ctx context.Context) error {
+	var errs []error
+
+	// Application under test depends on all members.
+	if ec.appContainer != nil {
+		errs = append(errs, ec.appContainer.Terminate(ctx))
+	}
+
+	// Async Microcks minion and Postman containers depend on Microcks.
+	if ec.asyncMinionContainer != nil {
+		errs = append(errs, ec.asyncMinionContainer.Terminate(ctx))
+	}
+	if ec.postmanContainer != nil {
+		errs = append(errs, ec.postmanContainer.Terminate(ctx))
+	}
+
 	// Main Microcks container.
-	if err := ec.microcksContainer.Terminate(ctx); err != nil {
-		return err
+	if ec.microcksContainer != nil {
+		errs = append(errs, ec.microcksContainer.Terminate(ctx))
 	}
 
-	// Postman container.
-	if ec.postmanEnabled {
-		if err := ec.postmanContainer.Terminate(ctx); err != nil {
-			return err
-		}
+	// Keycloak and brokers containers.
+	if ec.keycloakContainer != nil {
+		errs = append(errs, ec.keycloakContainer.Terminate(ctx))
+	}
+	if ec.kafkaContainer != nil {
+		errs = append(errs, ec.kafkaContainer.Terminate(ctx))
+	}
+	if ec.mqttContainer != nil {
+		errs = append(errs, ec.mqttContainer.Terminate(ctx))
+	}
+	if ec.amqpContainer != nil {
+		errs = append(errs, ec.amqpContainer.Terminate(ctx))
+	}
+	if ec.localStackContainer != nil {
+		errs = append(errs, ec.localStackContainer.Terminate(ctx))
+	}
+	if ec.googlePubSubContainer != nil {
+		errs = append(errs, ec.googlePubSubContainer.Terminate(ctx))
 	}
 
-	// Async Microcks minion container.
-	if ec.asyncEnabled {
-		if err := ec.asyncMinionContainer.Terminate(ctx); err != nil {
-			return err
+	// Network created by the ensemble, once no member is attached anymore.
+	if ec.ownedNetwork && ec.network != nil {
+		if err := ec.network.Remove(ctx); err != nil {
+			errs = append(errs, err)
+		} else {
+			cleanup.UntrackNetwork(ec.network)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // RunContainers creates instances of the Microcks Ensemble.
-// Using sequential start to avoid resource contention on CI systems with weaker hardware.
+// Members not depending on others (brokers, Keycloak and Postman) are started concurrently, then Microcks and
+// finally the Async Minion. Use WithSequentialStartup to avoid resource contention on CI systems with weaker hardware.
+// Started members are terminated when one of them fails to start.
 func RunContainers(ctx context.Context, opts ...Option) (*MicrocksContainersEnsemble, error) {
 	var err error
 
 	ensemble := &MicrocksContainersEnsemble{ctx: ctx}
 
-	// Options.
-	defaults := []Option{WithDefaultNetwork()}
-	options := append(defaults, opts...)
-	for _, opt := range options {
+	// Options are all validated, so that every misconfiguration is reported at once.
+	var errs []error
+	for _, opt := range opts {
 		if err = opt(ensemble); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, ensemble.validate()...)
+	if err = errors.Join(errs...); err != nil {
+		// Options may already have created the network.
+		return nil, ensemble.abort(err)
+	}
+
+	// Create a dedicated network when none has been provided.
+	if ensemble.network == nil {
+		createNetwork := WithDefaultNetwork()
+		if ensemble.reuseName != "" {
+			createNetwork = WithReusableNetwork(ensemble.reuseName + "-network")
+		}
+		if err = createNetwork(ensemble); err != nil {
 			return nil, err
 		}
 	}
+	if ensemble.reuseName != "" {
+		ensemble.applyReuse()
+	}
 
-	// Set microcks container env variables.
-	testCallbackURL := strings.Join([]string{"http://", microcks.DefaultNetworkAlias, ":8080"}, "")
-	postmanRunnerURL := strings.Join([]string{"http://", postman.DefaultNetworkAlias, ":3000"}, "")
-	asyncMinionURL := strings.Join([]string{"http://", async.DefaultNetworkAlias, ":8081"}, "")
+	ensemble.configureMembers()
 
-	ensemble.microcksContainerOptions.Add(microcks.WithEnv("TEST_CALLBACK_URL", testCallbackURL))
-	ensemble.microcksContainerOptions.Add(microcks.WithEnv("POSTMAN_RUNNER_URL", postmanRunnerURL))
-	ensemble.microcksContainerOptions.Add(microcks.WithEnv("ASYNC_MINION_URL", asyncMinionURL))
+	// Start independent members.
+	if err = ensemble.startAll(ensemble.independentStarters()); err != nil {
+		return nil, ensemble.abort(err)
+	}
+
+	// Authenticate module API calls with the realm service account.
+	if ensemble.keycloakEnabled {
+		keycloakEndpoint, err := ensemble.keycloakContainer.HttpEndpoint(ctx)
+		if err != nil {
+			return nil, ensemble.abort(err)
+		}
+		ensemble.microcksContainerOptions.Add(microcks.WithServiceAccount(
+			keycloakEndpoint,
+			keycloak.DefaultServiceAccount,
+			ensemble.keycloakRealm.ServiceAccountCredentials,
+		))
+	}
 
 	// Start default Microcks container.
 	if len(ensemble.hostAccessPorts) > 0 {
@@ -136,31 +327,274 @@ func RunContainers(ctx context.Context, opts ...Option) (*MicrocksContainersEnse
 			microcks.WithHostAccessPorts(ensemble.hostAccessPorts),
 		)
 	}
-	ensemble.microcksContainer, err = microcks.RunContainer(ctx, ensemble.microcksContainerOptions.list...)
+	if err = ensemble.addStaticHosts(ctx, &ensemble.microcksContainerOptions); err != nil {
+		return nil, ensemble.abort(err)
+	}
+	if ensemble.remoteMicrocksEndpoint != "" {
+		ensemble.microcksContainer, err = microcks.Connect(ctx, ensemble.remoteMicrocksEndpoint, ensemble.microcksContainerOptions.list...)
+	} else {
+		ensemble.microcksContainer, err = microcks.Run(ctx, microcks.DefaultImage, ensemble.microcksContainerOptions.list...)
+	}
 	if err != nil {
-		return nil, err
+		return nil, ensemble.abort(err)
 	}
 
-	// Start Postman container if enabled.
-	if ensemble.postmanEnabled {
-		ensemble.postmanContainer, err = postman.RunContainer(ctx, ensemble.postmanContainerOptions.list...)
+	// Start Microcks async minion container if enabled, as it needs Microcks.
+	if ensemble.asyncEnabled {
+		microcksHostPort := strings.Join([]string{microcks.DefaultNetworkAlias, ":8080"}, "")
+		ensemble.asyncMinionContainerOptions.Add(async.WithMicrocksHostPort(microcksHostPort))
+		if err = ensemble.addStaticHosts(ctx, &ensemble.asyncMinionContainerOptions); err != nil {
+			return nil, ensemble.abort(err)
+		}
+		ensemble.asyncMinionContainer, err = async.Run(ctx, async.DefaultImage, ensemble.asyncMinionContainerOptions.list...)
 		if err != nil {
-			return nil, err
+			return nil, ensemble.abort(err)
 		}
 	}
 
-	// Start Microcks async minion container if enabled.
-	if ensemble.asyncEnabled {
-		microcksHostPort := strings.Join([]string{microcks.DefaultNetworkAlias, ":8080"}, "")
-		ensemble.asyncMinionContainer, err = async.RunContainer(ctx, microcksHostPort, ensemble.asyncMinionContainerOptions.list...)
-		if err != nil {
-			return nil, err
+	// Start the application under test once mocks are available.
+	if ensemble.app != nil {
+		if err = ensemble.startApp(ctx); err != nil {
+			return nil, ensemble.abort(err)
+		}
+	}
+
+	// Wait for all members to be ready if asked.
+	if ensemble.readinessTimeout > 0 {
+		if err = ensemble.WaitReady(ctx, ensemble.readinessTimeout); err != nil {
+			return nil, ensemble.abort(err)
 		}
 	}
 
 	return ensemble, nil
 }
 
+// configureMembers wires members together using their network aliases, before any of them is started.
+func (ec *MicrocksContainersEnsemble) configureMembers() {
+	// Set microcks container env variables.
+	testCallbackURL := strings.Join([]string{"http://", microcks.DefaultNetworkAlias, ":8080"}, "")
+	postmanRunnerURL := strings.Join([]string{"http://", postman.DefaultNetworkAlias, ":3000"}, "")
+	asyncMinionURL := strings.Join([]string{"http://", async.DefaultNetworkAlias, ":8081"}, "")
+
+	ec.microcksContainerOptions.Add(microcks.WithEnv("TEST_CALLBACK_URL", testCallbackURL))
+	ec.microcksContainerOptions.Add(microcks.WithEnv("POSTMAN_RUNNER_URL", postmanRunnerURL))
+	ec.microcksContainerOptions.Add(microcks.WithEnv("ASYNC_MINION_URL", asyncMinionURL))
+
+	if ec.keycloakEnabled {
+		keycloakURL := strings.Join([]string{"http://", keycloak.DefaultNetworkAlias, ":8080"}, "")
+
+		// Tokens issuer must be the same from inside and outside the network.
+		ec.keycloakContainerOptions.Add(keycloak.WithEnv("KC_HOSTNAME_URL", keycloakURL))
+
+		ec.microcksContainerOptions.Add(microcks.WithEnv("KEYCLOAK_ENABLED", "true"))
+		ec.microcksContainerOptions.Add(microcks.WithEnv("KEYCLOAK_REALM", ec.keycloakRealm.Name))
+		ec.microcksContainerOptions.Add(microcks.WithEnv("KEYCLOAK_URL", keycloakURL))
+		ec.microcksContainerOptions.Add(microcks.WithEnv("KEYCLOAK_PUBLIC_URL", keycloakURL))
+
+		ec.asyncMinionContainerOptions.Add(async.WithEnv("KEYCLOAK_URL", keycloakURL))
+		ec.asyncMinionContainerOptions.Add(async.WithEnv("SERVICEACCOUNT", keycloak.DefaultServiceAccount))
+		ec.asyncMinionContainerOptions.Add(async.WithEnv("SERVICEACCOUNT_CREDENTIALS", ec.keycloakRealm.ServiceAccountCredentials))
+	}
+
+	if ec.kafkaEnabled {
+		ec.asyncMinionContainerOptions.Add(async.WithKafkaConnection(kafka.Connection{
+			BootstrapServers: strings.Join([]string{KafkaNetworkAlias, ":", KafkaBrokerPort}, ""),
+		}))
+	}
+
+	if ec.mqttEnabled {
+		ec.asyncMinionContainerOptions.Add(async.WithMQTTConnection(mqtt.Connection{
+			Server: strings.Join([]string{MQTTNetworkAlias, ":", nat.Port(MQTTBrokerPort).Port()}, ""),
+		}))
+	}
+
+	if ec.amqpEnabled {
+		ec.asyncMinionContainerOptions.Add(async.WithAMQPConnection(amqp.Connection{
+			Server:   strings.Join([]string{AMQPNetworkAlias, ":", nat.Port(AMQPBrokerPort).Port()}, ""),
+			Username: connection.FromValue(AMQPUsername),
+			Password: connection.FromValue(AMQPPassword),
+		}))
+	}
+
+	if len(ec.localStackServices) > 0 {
+		ec.localStackContainerOptions.Add(localstack.WithServices(ec.localStackServices...))
+
+		localStackConnection := amazonservice.Connection{
+			Region:           localstack.DefaultRegion,
+			EndpointOverride: strings.Join([]string{"http://", localstack.DefaultNetworkAlias, ":4566"}, ""),
+			AccessKey:        connection.FromValue(localstack.DefaultAccessKey),
+			SecretKey:        connection.FromValue(localstack.DefaultSecretKey),
+		}
+		for _, service := range ec.localStackServices {
+			switch strings.ToLower(service) {
+			case "sqs":
+				ec.asyncMinionContainerOptions.Add(async.WithAmazonSQSConnection(localStackConnection))
+			case "sns":
+				ec.asyncMinionContainerOptions.Add(async.WithAmazonSNSConnection(localStackConnection))
+			}
+		}
+	}
+
+	if ec.googlePubSubProject != "" {
+		ec.asyncMinionContainerOptions.Add(async.WithGooglePubSubConnection(googlepubsub.Connection{
+			Project:      ec.googlePubSubProject,
+			EmulatorHost: strings.Join([]string{GooglePubSubNetworkAlias, ":", nat.Port(GooglePubSubPort).Port()}, ""),
+		}))
+	}
+}
+
+// independentStarters returns the functions starting enabled members that don't depend on others.
+// Each of them only sets its own member.
+func (ec *MicrocksContainersEnsemble) independentStarters() []func() error {
+	ctx := ec.ctx
+
+	var starters []func() error
+	if ec.keycloakEnabled {
+		starters = append(starters, func() (err error) {
+			ec.keycloakContainer, err = keycloak.Run(ctx, keycloak.DefaultImage, ec.keycloakContainerOptions.list...)
+			return err
+		})
+	}
+	if ec.kafkaEnabled {
+		starters = append(starters, func() (err error) {
+			opts := append(ec.kafkaContainerOptions.list,
+				testcontainers.CustomizeRequestOption(registry.Apply),
+				testcontainers.CustomizeRequestOption(cleanup.Apply),
+			)
+			ec.kafkaContainer, err = kafkaTC.RunContainer(ctx, opts...)
+			if ec.kafkaContainer != nil {
+				cleanup.TrackContainer(ec.kafkaContainer)
+			}
+			return err
+		})
+	}
+	if ec.mqttEnabled {
+		starters = append(starters, func() (err error) {
+			ec.mqttContainer, err = runMQTTBroker(ctx, ec.mqttContainerOptions.list...)
+			return err
+		})
+	}
+	if ec.amqpEnabled {
+		starters = append(starters, func() (err error) {
+			ec.amqpContainer, err = runAMQPBroker(ctx, ec.amqpContainerOptions.list...)
+			return err
+		})
+	}
+	if len(ec.localStackServices) > 0 {
+		starters = append(starters, func() (err error) {
+			ec.localStackContainer, err = localstack.Run(ctx, localstack.DefaultImage, ec.localStackContainerOptions.list...)
+			return err
+		})
+	}
+	if ec.googlePubSubProject != "" {
+		starters = append(starters, func() (err error) {
+			ec.googlePubSubContainer, err = runGooglePubSubEmulator(ctx, ec.googlePubSubProject, ec.googlePubSubContainerOptions.list...)
+			return err
+		})
+	}
+	if ec.postmanEnabled {
+		starters = append(starters, func() (err error) {
+			ec.postmanContainer, err = postman.Run(ctx, postman.DefaultImage, ec.postmanContainerOptions.list...)
+			return err
+		})
+	}
+	return starters
+}
+
+// startAll runs starters concurrently, or one after the other with sequential startup, and aggregates errors.
+func (ec *MicrocksContainersEnsemble) startAll(starters []func() error) error {
+	if ec.sequentialStartup {
+		for _, start := range starters {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(starters))
+	var wg sync.WaitGroup
+	for i, start := range starters {
+		wg.Add(1)
+		go func(i int, start func() error) {
+			defer wg.Done()
+			errs[i] = start()
+		}(i, start)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// abort terminates already started members and returns the startup error.
+func (ec *MicrocksContainersEnsemble) abort(err error) error {
+	_ = ec.Terminate(ec.ctx)
+	return err
+}
+
+// WithLogger sets the logger of all members lifecycle and module messages, e.g. testcontainers.TestLogger(t).
+func WithLogger(logger testcontainers.Logging) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.microcksContainerOptions.Add(microcks.WithLogger(logger))
+		for _, options := range e.memberOptions() {
+			options.Add(testcontainers.WithLogger(logger))
+		}
+		return nil
+	}
+}
+
+// WithLabels adds labels to every container and network created by the ensemble, including the application under
+// test, e.g. for cleanup policies or cost attribution. Networks are labeled when created after this option.
+func WithLabels(labels map[string]string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		if e.labels == nil {
+			e.labels = make(map[string]string)
+		}
+		for key, value := range labels {
+			e.labels[key] = value
+		}
+		for _, options := range e.memberOptions() {
+			options.Add(microcks.WithLabels(labels))
+		}
+		return nil
+	}
+}
+
+// WithDebugLogging raises the Microcks and Async Minion log level to DEBUG, e.g. to diagnose dispatching rules not
+// matching incoming requests.
+func WithDebugLogging() Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.microcksContainerOptions.Add(microcks.WithDebugLogging())
+		e.asyncMinionContainerOptions.Add(async.WithDebugLogging())
+		return nil
+	}
+}
+
+// networkLabels returns the labels of networks created by the ensemble.
+func (ec *MicrocksContainersEnsemble) networkLabels() map[string]string {
+	labels := map[string]string{NetworkLabel: "true"}
+	for key, value := range ec.labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// WithLifecycleHooks adds hooks to the lifecycle of given member, e.g. to seed broker topics once MemberKafka is
+// started, or capture logs before a member is terminated.
+func WithLifecycleHooks(name Member, hooks ...testcontainers.ContainerLifecycleHooks) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		options, err := e.optionsOf(name)
+		if err != nil {
+			return err
+		}
+		options.Add(testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+			req.LifecycleHooks = append(req.LifecycleHooks, hooks...)
+			return nil
+		}))
+		return nil
+	}
+}
+
 // WithMicrocksImage helps to use specific Microcks image.
 func WithMicrocksImage(image string) Option {
 	return func(e *MicrocksContainersEnsemble) error {
@@ -169,7 +603,61 @@ func WithMicrocksImage(image string) Option {
 	}
 }
 
-// WithAsynncFature enables the Async Feature container with default container image (deduced from Microcks main one).
+// WithRemoteMicrocks binds the ensemble to an already deployed Microcks instance reachable at given HTTP endpoint,
+// instead of starting a Microcks container, see microcks.Connect. Brokers, as well as the Async, Postman and Keycloak
+// features, need a local Microcks container and are rejected. Use WithMicrocksOptions to provide microcks.WithServiceAccount credentials.
+func WithRemoteMicrocks(httpEndpoint string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.remoteMicrocksEndpoint = httpEndpoint
+		return nil
+	}
+}
+
+// WithMicrocksOptions applies given options to the Microcks container, e.g. microcks.WithEnv or
+// testcontainers.WithImage.
+func WithMicrocksOptions(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.microcksContainerOptions.Add(opt)
+		}
+		return nil
+	}
+}
+
+// WithMinionOptions applies given options to the Async Minion container, when the Async Feature is enabled.
+func WithMinionOptions(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.asyncMinionContainerOptions.Add(opt)
+		}
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithPostmanOptions applies given options to the Postman container, when Postman is enabled.
+func WithPostmanOptions(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.postmanContainerOptions.Add(opt)
+		}
+		e.postmanCustomized = true
+		return nil
+	}
+}
+
+// WithKeycloakOptions applies given options to the Keycloak container, when the Keycloak Feature is enabled.
+func WithKeycloakOptions(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.keycloakContainerOptions.Add(opt)
+		}
+		e.keycloakCustomized = true
+		return nil
+	}
+}
+
+// WithAsyncFeature enables the Async Feature container with default container image (deduced from Microcks main one).
 func WithAsyncFeature() Option {
 	return func(e *MicrocksContainersEnsemble) error {
 		e.asyncEnabled = true
@@ -186,6 +674,108 @@ func WithAsyncFeatureImage(image string) Option {
 	}
 }
 
+// WithKeycloakFeature enables the Keycloak container with the default Microcks realm, and configures
+// Microcks and the Async Feature to require authentication.
+func WithKeycloakFeature() Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.keycloakEnabled = true
+		e.keycloakRealm = &keycloak.Realm{
+			Name:                      keycloak.DefaultRealm,
+			ServiceAccountCredentials: keycloak.DefaultServiceAccountCredentials,
+		}
+		return nil
+	}
+}
+
+// WithKeycloak enables the Keycloak container importing given realm export, and configures Microcks and
+// the Async Feature to require authentication with it. The realm has to define the keycloak.DefaultServiceAccount
+// client, with its secret, for the module and the Async Feature to call Microcks API.
+func WithKeycloak(realmFile string) Option {
+	return func(e *MicrocksContainersEnsemble) (err error) {
+		e.keycloakRealm, err = keycloak.ReadRealm(realmFile)
+		if err != nil {
+			return err
+		}
+		e.keycloakContainerOptions.Add(keycloak.WithRealm(realmFile))
+		e.keycloakEnabled = true
+		return nil
+	}
+}
+
+// WithKafka starts a Kafka broker on the ensemble network and connects the Async Feature to it,
+// enabling the Async Feature. Given options customize the Kafka container.
+func WithKafka(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.kafkaContainerOptions.Add(opt)
+		}
+		e.kafkaEnabled = true
+		e.asyncEnabled = true
+		return nil
+	}
+}
+
+// WithMQTTBroker starts a Mosquitto MQTT broker on the ensemble network and connects the Async Feature to it,
+// enabling the Async Feature. Given options customize the broker container.
+func WithMQTTBroker(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.mqttContainerOptions.Add(opt)
+		}
+		e.mqttEnabled = true
+		e.asyncEnabled = true
+		return nil
+	}
+}
+
+// WithAMQPBroker starts a RabbitMQ AMQP broker on the ensemble network, with a user for the Async Feature,
+// and connects the Async Feature to it, enabling the Async Feature. Given options customize the broker container.
+func WithAMQPBroker(opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.amqpContainerOptions.Add(opt)
+		}
+		e.amqpEnabled = true
+		e.asyncEnabled = true
+		return nil
+	}
+}
+
+// WithLocalStack starts LocalStack on the ensemble network, emulating given AWS services ("sqs" and "sns" when
+// none is given), and connects the Async Feature to SQS and SNS, enabling the Async Feature.
+func WithLocalStack(services ...string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		if len(services) == 0 {
+			services = []string{"sqs", "sns"}
+		}
+		e.localStackServices = services
+		e.asyncEnabled = true
+		return nil
+	}
+}
+
+// WithGooglePubSubEmulator starts the Google Cloud Pub/Sub emulator for given project on the ensemble network,
+// and connects the Async Feature to it, enabling the Async Feature. Given options customize the emulator container.
+func WithGooglePubSubEmulator(project string, opts ...testcontainers.ContainerCustomizer) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			e.googlePubSubContainerOptions.Add(opt)
+		}
+		e.googlePubSubProject = project
+		e.asyncEnabled = true
+		return nil
+	}
+}
+
+// WithSequentialStartup starts ensemble members one after the other, to avoid resource contention on CI systems
+// with weaker hardware.
+func WithSequentialStartup() Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.sequentialStartup = true
+		return nil
+	}
+}
+
 // WithPostman allows to enable Postman container.
 func WithPostman(enable bool) Option {
 	return func(e *MicrocksContainersEnsemble) error {
@@ -203,13 +793,19 @@ func WithPostmanImage(image string) Option {
 	}
 }
 
-// WithDefaultNetwork allows to use a default network.
+// WithDefaultNetwork allows to use a default network, created with a random name and removed on termination.
+// It is used when no network is provided.
 func WithDefaultNetwork() Option {
 	return func(e *MicrocksContainersEnsemble) (err error) {
-		e.network, err = network.New(e.ctx, network.WithCheckDuplicate())
+		e.network, err = network.New(e.ctx,
+			network.WithCheckDuplicate(),
+			network.WithLabels(e.networkLabels()),
+		)
 		if err != nil {
 			return err
 		}
+		cleanup.TrackNetwork(e.network)
+		e.ownedNetwork = true
 		return networkOptionApply(e)
 	}
 }
@@ -223,10 +819,15 @@ func WithNetwork(network *testcontainers.DockerNetwork) Option {
 }
 
 func networkOptionApply(e *MicrocksContainersEnsemble) error {
-	netCustReqOpt := network.WithNetwork([]string{microcks.DefaultNetworkAlias}, e.network)
-	e.microcksContainerOptions.Add(netCustReqOpt)
-	e.postmanContainerOptions.Add(netCustReqOpt)
-	e.asyncMinionContainerOptions.Add(netCustReqOpt)
+	e.microcksContainerOptions.Add(network.WithNetwork([]string{microcks.DefaultNetworkAlias}, e.network))
+	e.postmanContainerOptions.Add(network.WithNetwork([]string{postman.DefaultNetworkAlias}, e.network))
+	e.asyncMinionContainerOptions.Add(network.WithNetwork([]string{async.DefaultNetworkAlias}, e.network))
+	e.keycloakContainerOptions.Add(network.WithNetwork([]string{keycloak.DefaultNetworkAlias}, e.network))
+	e.kafkaContainerOptions.Add(network.WithNetwork([]string{KafkaNetworkAlias}, e.network))
+	e.mqttContainerOptions.Add(network.WithNetwork([]string{MQTTNetworkAlias}, e.network))
+	e.amqpContainerOptions.Add(network.WithNetwork([]string{AMQPNetworkAlias}, e.network))
+	e.localStackContainerOptions.Add(network.WithNetwork([]string{localstack.DefaultNetworkAlias}, e.network))
+	e.googlePubSubContainerOptions.Add(network.WithNetwork([]string{GooglePubSubNetworkAlias}, e.network))
 	return nil
 }
 
@@ -250,6 +851,40 @@ func WithSecondaryArtifact(artifactFilePath string) Option {
 	}
 }
 
+// WithSnapshots provides paths to Microcks repository snapshots that will be imported within the Microcks container.
+// Once it will be started and healthy.
+func WithSnapshots(snapshotFilePaths ...string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.microcksContainerOptions.Add(microcks.WithSnapshots(snapshotFilePaths...))
+		return nil
+	}
+}
+
+// WithMainRemoteArtifact provides an URL to an artifact that will be downloaded and imported as main one
+// within the Microcks container.
+// Once it will be started and healthy.
+func WithMainRemoteArtifact(remoteArtifactURL string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.microcksContainerOptions.Add(microcks.WithMainRemoteArtifact(remoteArtifactURL))
+		return nil
+	}
+}
+
+// WithSecondaryRemoteArtifact provides an URL to an artifact that will be downloaded and imported as secondary one
+// within the Microcks container.
+// Once it will be started and healthy.
+func WithSecondaryRemoteArtifact(remoteArtifactURL string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.microcksContainerOptions.Add(microcks.WithSecondaryRemoteArtifact(remoteArtifactURL))
+		return nil
+	}
+}
+
+// WithHostAccess allows ensemble members to reach given ports of the host, see microcks.HostEndpoint.
+func WithHostAccess(ports ...int) Option {
+	return WithHostAccessPorts(ports)
+}
+
 // WithHostAccessPorts helps to open connections between Microcks, Postman or Microcks async
 // to the user's host ports.
 func WithHostAccessPorts(hostAccessPorts []int) Option {
@@ -259,18 +894,93 @@ func WithHostAccessPorts(hostAccessPorts []int) Option {
 	}
 }
 
+// WithProxy allows to route outgoing traffic of Microcks, Postman and Microcks async containers
+// through a corporate proxy.
+func WithProxy(httpProxy, httpsProxy, noProxy string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		// Ensemble members must reach each other directly.
+		members := []string{
+			microcks.DefaultNetworkAlias,
+			postman.DefaultNetworkAlias,
+			async.DefaultNetworkAlias,
+			keycloak.DefaultNetworkAlias,
+			KafkaNetworkAlias,
+			MQTTNetworkAlias,
+			AMQPNetworkAlias,
+			localstack.DefaultNetworkAlias,
+			GooglePubSubNetworkAlias,
+		}
+		if noProxy != "" {
+			members = append(members, noProxy)
+		}
+		noProxy = strings.Join(members, ",")
+
+		e.microcksContainerOptions.Add(microcks.WithProxy(httpProxy, httpsProxy, noProxy))
+		e.postmanContainerOptions.Add(postman.WithProxy(httpProxy, httpsProxy, noProxy))
+		e.asyncMinionContainerOptions.Add(async.WithProxy(httpProxy, httpsProxy, noProxy))
+		return nil
+	}
+}
+
 // WithKafkaConnection configures the Kafka connection.
 func WithKafkaConnection(connection kafka.Connection) Option {
 	return func(e *MicrocksContainersEnsemble) error {
 		e.asyncMinionContainerOptions.Add(async.WithKafkaConnection(connection))
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithMQTTConnection configures the MQTT connection.
+func WithMQTTConnection(connection mqtt.Connection) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.asyncMinionContainerOptions.Add(async.WithMQTTConnection(connection))
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithAMQPConnection configures the AMQP connection.
+func WithAMQPConnection(connection amqp.Connection) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.asyncMinionContainerOptions.Add(async.WithAMQPConnection(connection))
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithAmazonSQSConnection configures the Amazon SQS connection.
+func WithAmazonSQSConnection(connection amazonservice.Connection) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.asyncMinionContainerOptions.Add(async.WithAmazonSQSConnection(connection))
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithAmazonSNSConnection configures the Amazon SNS connection.
+func WithAmazonSNSConnection(connection amazonservice.Connection) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.asyncMinionContainerOptions.Add(async.WithAmazonSNSConnection(connection))
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithGooglePubSubConnection configures the Google Cloud Pub/Sub connection.
+func WithGooglePubSubConnection(connection googlepubsub.Connection) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.asyncMinionContainerOptions.Add(async.WithGooglePubSubConnection(connection))
+		e.minionCustomized = true
 		return nil
 	}
 }
 
-// WithSecret creates a new secret.
+// WithSecret creates a new secret within the Microcks container.
+// Once it will be started and healthy, before artifacts are imported.
 func WithSecret(s client.Secret) Option {
 	return func(e *MicrocksContainersEnsemble) error {
-		e.asyncMinionContainerOptions.Add(microcks.WithSecret(s))
+		e.microcksContainerOptions.Add(microcks.WithSecret(s))
 		return nil
 	}
 }
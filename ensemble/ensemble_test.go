@@ -2,15 +2,22 @@ package ensemble_test
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	kafkaTC "github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"microcks.io/go-client"
+	microcks "microcks.io/testcontainers-go"
 	"microcks.io/testcontainers-go/ensemble"
 	"microcks.io/testcontainers-go/ensemble/async/connection/kafka"
+	"microcks.io/testcontainers-go/ensemble/postman"
 	"microcks.io/testcontainers-go/internal/test"
 )
 
@@ -21,6 +28,7 @@ func TestMockingFunctionalityAtStartup(t *testing.T) {
 	ec, err := ensemble.RunContainers(ctx,
 		ensemble.WithMainArtifact("../testdata/apipastries-openapi.yaml"),
 		ensemble.WithSecondaryArtifact("../testdata/apipastries-postman-collection.json"),
+		ensemble.WithMicrocksOptions(microcks.WithEnv("MAX_UPLOAD_FILE_SIZE", "10MB")),
 	)
 	require.NoError(t, err)
 
@@ -32,11 +40,165 @@ func TestMockingFunctionalityAtStartup(t *testing.T) {
 	})
 
 	// Tests & assertions.
+	require.NoError(t, ec.WaitReady(ctx, 30*time.Second))
 	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
 	test.MockEndpoints(t, ctx, ec.GetMicrocksContainer())
+
+	// Ensemble delegates endpoints to its members.
+	restEndpoint, err := ec.RestMockEndpoint(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	expectedRestEndpoint, err := ec.GetMicrocksContainer().RestMockEndpoint(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, expectedRestEndpoint, restEndpoint)
+	_, err = ec.WSMockEndpoint(ctx, "Pastry orders API", "0.1.0", "SUBSCRIBE pastry/orders")
+	require.Error(t, err)
+	test.MicrocksMockingFunctionality(t, ctx, ec.GetMicrocksContainer())
+}
+
+func TestDefaultNetworkLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(ctx, ensemble.WithLabels(map[string]string{"team": "payments"}))
+	require.NoError(t, err)
+
+	provider, err := testcontainers.NewDockerProvider()
+	require.NoError(t, err)
+	t.Cleanup(func() { provider.Close() })
+
+	// Network and members are labeled by the ensemble.
+	networkName := ec.GetNetwork().Name
+	resource, err := provider.GetNetwork(ctx, testcontainers.NetworkRequest{Name: networkName})
+	require.NoError(t, err)
+	require.Equal(t, "true", resource.Labels[ensemble.NetworkLabel])
+	require.Equal(t, "payments", resource.Labels["team"])
+
+	inspect, err := ec.GetMicrocksContainer().Inspect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "payments", inspect.Config.Labels["team"])
+
+	// Network is removed on termination.
+	require.NoError(t, ec.Terminate(ctx))
+	_, err = provider.GetNetwork(ctx, testcontainers.NetworkRequest{Name: networkName})
+	require.Error(t, err)
+}
+
+func TestMinimalPreset(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(ctx,
+		ensemble.Minimal(),
+		ensemble.WithLogger(testcontainers.TestLogger(t)),
+		ensemble.WithMainArtifact("../testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+	test.MockEndpoints(t, ctx, ec.GetMicrocksContainer())
+}
+
+func TestSnapshotRestoreFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(ctx,
+		ensemble.WithMainArtifact("../testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	snapshot, err := ec.Snapshot(ctx)
+	require.NoError(t, err)
+
+	status, err := ec.GetMicrocksContainer().ImportAsMainArtifact(ctx, "../testdata/pastry-orders-asyncapi.yaml")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, status)
+	require.Equal(t, 2, countServices(t, ctx, ec))
+
+	require.NoError(t, ec.Restore(ctx, snapshot))
+	require.Equal(t, 1, countServices(t, ctx, ec))
 	test.MicrocksMockingFunctionality(t, ctx, ec.GetMicrocksContainer())
 }
 
+func countServices(t *testing.T, ctx context.Context, ec *ensemble.MicrocksContainersEnsemble) int {
+	endpoint, err := ec.GetMicrocksContainer().HttpEndpoint(ctx)
+	require.NoError(t, err)
+
+	resp, err := http.Get(endpoint + "/api/services/count")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var count struct {
+		Counter int `json:"counter"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&count))
+	return count.Counter
+}
+
+func TestReuseFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers, started twice.
+	ec, err := ensemble.RunContainers(ctx, ensemble.WithReuse("microcks-reuse-test"))
+	require.NoError(t, err)
+	reused, err := ensemble.RunContainers(ctx, ensemble.WithReuse("microcks-reuse-test"))
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	require.Equal(t, ec.GetNetwork().Name, reused.GetNetwork().Name)
+	require.Equal(t, ec.GetMicrocksContainer().GetContainerID(), reused.GetMicrocksContainer().GetContainerID())
+	test.ConfigRetrieval(t, ctx, reused.GetMicrocksContainer())
+}
+
+func TestSecretFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	s := client.Secret{
+		Name:        "test-secret",
+		Description: "test-secret",
+	}
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(ctx,
+		ensemble.WithMainArtifact("../testdata/apipastries-openapi.yaml"),
+		ensemble.WithSecret(s),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.SecretRetrieval(t, ctx, ec.GetMicrocksContainer(), &s)
+}
+
 func TestPostmanContractTestingFunctionality(t *testing.T) {
 	ctx := context.Background()
 
@@ -80,19 +242,22 @@ func TestPostmanContractTestingFunctionality(t *testing.T) {
 
 	// Cleanup containers.
 	t.Cleanup(func() {
-		if err := ec.GetMicrocksContainer().Terminate(ctx); err != nil {
-			t.Fatalf("failed to terminate container: %s", err)
-		}
 		if err := badImpl.Terminate(ctx); err != nil {
 			t.Fatalf("failed to terminate container: %s", err)
 		}
 		if err := goodImpl.Terminate(ctx); err != nil {
 			t.Fatalf("failed to terminate container: %s", err)
 		}
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
 	})
 
 	// Tests & assertions.
 	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+	postmanEndpoint, err := ec.GetPostmanContainer().HttpEndpoint(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, postmanEndpoint)
 	test.MicrocksContractTestingFunctionality(
 		t,
 		ctx,
@@ -102,6 +267,51 @@ func TestPostmanContractTestingFunctionality(t *testing.T) {
 	)
 }
 
+func TestAppContainerFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithMainArtifact("../testdata/apipastries-openapi.yaml"),
+		ensemble.WithSecondaryArtifact("../testdata/apipastries-postman-collection.json"),
+		ensemble.WithPostman(true),
+		ensemble.WithAppContainer(testcontainers.ContainerRequest{
+			Image:        "quay.io/microcks/contract-testing-demo:03",
+			ExposedPorts: []string{"3003/tcp"},
+			Env: map[string]string{
+				"PASTRIES_URL": `{{ .RestMockEndpoint "API Pastries" "0.0.1" }}`,
+			},
+			WaitingFor: wait.ForLog("Example app listening on port 3003"),
+		}, "good-impl"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	require.NotNil(t, ec.GetAppContainer())
+	require.Equal(t, "http://good-impl:3003", ec.AppEndpoint())
+
+	env, err := ec.GetAppContainer().Inspect(ctx)
+	require.NoError(t, err)
+	require.Contains(t, env.Config.Env, "PASTRIES_URL=http://microcks:8080/rest/API%20Pastries/0.0.1")
+
+	testRequest := client.TestRequest{
+		ServiceId:    "API Pastries:0.0.1",
+		RunnerType:   client.TestRunnerTypePOSTMAN,
+		TestEndpoint: ec.AppEndpoint(),
+		Timeout:      3000,
+	}
+	testResult, err := ec.GetMicrocksContainer().TestEndpoint(ctx, &testRequest)
+	require.NoError(t, err)
+	require.True(t, testResult.Success)
+}
+
 func TestAsyncFeatureSetup(t *testing.T) {
 	ctx := context.Background()
 
@@ -122,6 +332,88 @@ func TestAsyncFeatureSetup(t *testing.T) {
 
 	// Tests & assertions.
 	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+
+	logs, err := io.ReadAll(ec.Logs(ctx))
+	require.NoError(t, err)
+	require.Contains(t, string(logs), "[microcks] ")
+	require.Contains(t, string(logs), "[minion] ")
+}
+
+func TestStaticHostResolution(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithAsyncFeature(),
+		ensemble.WithStaticHostResolution(),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	inspect, err := ec.GetAsyncMinionContainer().Inspect(ctx)
+	require.NoError(t, err)
+
+	microcksInspect, err := ec.GetMicrocksContainer().Inspect(ctx)
+	require.NoError(t, err)
+	expectedHost := microcks.DefaultNetworkAlias + ":" + microcksInspect.NetworkSettings.Networks[ec.GetNetwork().Name].IPAddress
+	require.Contains(t, inspect.HostConfig.ExtraHosts, expectedHost)
+	require.NoError(t, ec.WaitReady(ctx, 30*time.Second))
+}
+
+func TestKeycloakFeatureSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithKeycloakFeature(),
+		ensemble.WithMainArtifact("../testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.KeycloakConfigRetrieval(t, ctx, ec.GetMicrocksContainer(), true)
+
+	// API calls are authenticated with the service account.
+	status, err := ec.GetMicrocksContainer().ImportAsSecondaryArtifact(ctx, "../testdata/apipastries-postman-collection.json")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, status)
+}
+
+func TestKeycloakRealmSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithKeycloak("keycloak/microcks-realm.json"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.KeycloakConfigRetrieval(t, ctx, ec.GetMicrocksContainer(), true)
 }
 
 func TestAsyncFeatureMockingFunctionality(t *testing.T) {
@@ -203,3 +495,212 @@ func TestAsyncKafkaMockingFunctionality(t *testing.T) {
 		ec.GetAsyncMinionContainer(),
 	)
 }
+
+func TestKafkaFeatureMockingFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	kafkaStarted := false
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithKafka(),
+		ensemble.WithMainArtifact("../testdata/pastry-orders-asyncapi.yaml"),
+		ensemble.WithLifecycleHooks(ensemble.MemberKafka, testcontainers.ContainerLifecycleHooks{
+			PostStarts: []testcontainers.ContainerHook{
+				func(ctx context.Context, c testcontainers.Container) error {
+					kafkaStarted = true
+					return nil
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	require.True(t, kafkaStarted)
+	require.NoError(t, ec.WaitReady(ctx, 30*time.Second))
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+	test.MicrocksAsyncKafkaMockingFunctionality(
+		t,
+		ctx,
+		ec.GetKafkaContainer(),
+		ec.GetAsyncMinionContainer(),
+	)
+}
+
+func TestRestartFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithKafka(),
+		ensemble.WithMainArtifact("../testdata/pastry-orders-asyncapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	require.Error(t, ec.Restart(ctx, ensemble.MemberPostman))
+	require.NoError(t, ec.Restart(ctx, ensemble.MemberKafka))
+	require.NoError(t, ec.WaitReady(ctx, 60*time.Second))
+	test.MicrocksAsyncKafkaMockingFunctionality(
+		t,
+		ctx,
+		ec.GetKafkaContainer(),
+		ec.GetAsyncMinionContainer(),
+	)
+}
+
+func TestLifecycleHooksUnknownMember(t *testing.T) {
+	_, err := ensemble.RunContainers(context.Background(), ensemble.WithLifecycleHooks("unknown"))
+	require.Error(t, err)
+}
+
+func TestOptionsValidation(t *testing.T) {
+	_, err := ensemble.RunContainers(context.Background(),
+		ensemble.WithAsyncFeature(),
+		ensemble.WithKafkaConnection(kafka.Connection{}),
+		ensemble.WithPostmanOptions(postman.WithLabels(map[string]string{"team": "payments"})),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing bootstrap servers")
+	require.Contains(t, err.Error(), "Postman options need the Postman container")
+}
+
+func TestMQTTBrokerFeatureSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithMQTTBroker(),
+		ensemble.WithMainArtifact("../testdata/pastry-orders-asyncapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+	require.NotNil(t, ec.GetMQTTBrokerContainer())
+	require.Equal(t,
+		"PastryordersAPI-0.1.0-pastry/orders",
+		ec.MQTTMockTopic("Pastry orders API", "0.1.0", "SUBSCRIBE pastry/orders"),
+	)
+}
+
+func TestAMQPBrokerFeatureSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithAMQPBroker(),
+		ensemble.WithMainArtifact("../testdata/pastry-orders-asyncapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+	require.NotNil(t, ec.GetAMQPBrokerContainer())
+	require.Equal(t,
+		"PastryordersAPI-0.1.0-pastry/orders",
+		ec.GetAsyncMinionContainer().AMQPMockDestination("Pastry orders API", "0.1.0", "SUBSCRIBE pastry/orders"),
+	)
+}
+
+func TestLocalStackFeatureSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithLocalStack("sqs", "sns"),
+		ensemble.WithMainArtifact("../testdata/pastry-orders-asyncapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+
+	localStack := ec.GetLocalStackContainer()
+	require.NotNil(t, localStack)
+
+	queueURL, err := localStack.CreateQueue(ctx, "pastry-orders-listener")
+	require.NoError(t, err)
+	require.Contains(t, queueURL, "pastry-orders-listener")
+
+	topicArn, err := localStack.CreateTopic(ctx, "pastry-orders-events")
+	require.NoError(t, err)
+	subscriptionArn, err := localStack.Subscribe(ctx, topicArn, "pastry-orders-listener")
+	require.NoError(t, err)
+	require.NotEmpty(t, subscriptionArn)
+
+	require.Equal(t,
+		"PastryordersAPI-0.1.0-pastry-orders",
+		ec.GetAsyncMinionContainer().SQSMockQueue("Pastry orders API", "0.1.0", "SUBSCRIBE pastry/orders"),
+	)
+}
+
+func TestGooglePubSubEmulatorFeatureSetup(t *testing.T) {
+	ctx := context.Background()
+
+	// Ensemble containers.
+	ec, err := ensemble.RunContainers(
+		ctx,
+		ensemble.WithGooglePubSubEmulator("my-project"),
+		ensemble.WithMainArtifact("../testdata/pastry-orders-asyncapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	// Cleanup containers.
+	t.Cleanup(func() {
+		if err := ec.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Tests & assertions.
+	test.ConfigRetrieval(t, ctx, ec.GetMicrocksContainer())
+
+	emulatorHost, err := ec.GooglePubSubEmulatorEndpoint(ctx)
+	require.NoError(t, err)
+
+	// Emulator answers on its REST endpoint.
+	resp, err := http.Get("http://" + emulatorHost + "/v1/projects/my-project/topics")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package keycloak
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/redact"
+	"microcks.io/testcontainers-go/internal/registry"
+)
+
+const (
+	// DefaultImage represents the default Keycloak image.
+	DefaultImage = "quay.io/keycloak/keycloak:24.0.4"
+
+	// DefaultHTTPPort represents the default Keycloak HTTP port.
+	DefaultHTTPPort = "8080/tcp"
+
+	// DefaultNetworkAlias represents the default network alias of the the KeycloakContainer.
+	DefaultNetworkAlias = "keycloak"
+
+	// DefaultRealm represents the name of the default Microcks realm.
+	DefaultRealm = "microcks"
+
+	// DefaultServiceAccount represents the client id of the default Microcks realm service account.
+	DefaultServiceAccount = "microcks-serviceaccount"
+
+	// DefaultServiceAccountCredentials represents the client secret of the default Microcks realm service account.
+	DefaultServiceAccountCredentials = "ab54d329-e435-41ae-a900-ec6b3fe15c54"
+
+	realmImportPath = "/opt/keycloak/data/import/"
+)
+
+//go:embed microcks-realm.json
+var defaultRealm []byte
+
+// KeycloakContainer represents the Keycloak container type used in the ensemble.
+type KeycloakContainer struct {
+	testcontainers.Container
+}
+
+// RunContainer runs the Keycloak container, importing the default Microcks realm.
+// Deprecated: use Run instead.
+func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*KeycloakContainer, error) {
+	return Run(ctx, DefaultImage, opts...)
+}
+
+// Run runs the Keycloak container, importing the default Microcks realm, using given image.
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*KeycloakContainer, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        img,
+			ExposedPorts: []string{DefaultHTTPPort},
+			Cmd:          []string{"start-dev", "--import-realm"},
+			Env: map[string]string{
+				"KEYCLOAK_ADMIN":          "admin",
+				"KEYCLOAK_ADMIN_PASSWORD": "admin",
+			},
+			Files: []testcontainers.ContainerFile{
+				{
+					Reader:            bytes.NewReader(defaultRealm),
+					ContainerFilePath: realmImportPath + "microcks-realm.json",
+					FileMode:          0o644,
+				},
+			},
+			WaitingFor: wait.ForHTTP("/realms/" + DefaultRealm).WithPort(DefaultHTTPPort),
+		},
+		Started: true,
+	}
+
+	for _, opt := range opts {
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error starting Keycloak container (%s): %w", redact.Request(req), err)
+	}
+
+	return &KeycloakContainer{Container: container}, nil
+}
+
+// WithLabels adds labels to the Keycloak container, e.g. for cleanup policies or cost attribution.
+func WithLabels(labels map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		for key, value := range labels {
+			req.Labels[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithLifecycleHooks adds hooks to the Keycloak container lifecycle, e.g. to run initialization once it is started
+// or capture logs before it is terminated.
+func WithLifecycleHooks(hooks ...testcontainers.ContainerLifecycleHooks) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, hooks...)
+
+		return nil
+	}
+}
+
+// WithRequestModifier modifies the Keycloak container request with given function, e.g. to tweak settings not
+// covered by the options of the package. It is applied in order with other options.
+func WithRequestModifier(modifier func(*testcontainers.GenericContainerRequest)) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		modifier(req)
+		return nil
+	}
+}
+
+// WithEnv allows to add an environment variable.
+func WithEnv(key, value string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		req.Env[key] = value
+
+		return nil
+	}
+}
+
+// Realm represents the settings of a Keycloak realm export used by Microcks.
+type Realm struct {
+	// Name represents the realm name.
+	Name string
+
+	// ServiceAccountCredentials represents the secret of the DefaultServiceAccount client in the realm, if any.
+	ServiceAccountCredentials string
+}
+
+// ReadRealm reads the settings of a Keycloak realm export file.
+func ReadRealm(realmFile string) (*Realm, error) {
+	data, err := os.ReadFile(realmFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Keycloak realm file: %w", err)
+	}
+
+	var export struct {
+		Realm   string `json:"realm"`
+		Clients []struct {
+			ClientID string `json:"clientId"`
+			Secret   string `json:"secret"`
+		} `json:"clients"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("error decoding Keycloak realm file: %w", err)
+	}
+	if export.Realm == "" {
+		return nil, fmt.Errorf("error decoding Keycloak realm file: missing realm name")
+	}
+
+	realm := &Realm{Name: export.Realm}
+	for _, c := range export.Clients {
+		if c.ClientID == DefaultServiceAccount {
+			realm.ServiceAccountCredentials = c.Secret
+		}
+	}
+	return realm, nil
+}
+
+// WithRealm imports given realm export file instead of the default Microcks realm.
+func WithRealm(realmFile string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		realm, err := ReadRealm(realmFile)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(realmFile)
+		if err != nil {
+			return fmt.Errorf("error reading Keycloak realm file: %w", err)
+		}
+
+		for i, f := range req.Files {
+			if f.ContainerFilePath == realmImportPath+"microcks-realm.json" {
+				req.Files[i].Reader = bytes.NewReader(data)
+			}
+		}
+		req.WaitingFor = wait.ForHTTP("/realms/" + realm.Name).WithPort(DefaultHTTPPort)
+
+		return nil
+	}
+}
+
+// HttpEndpoint allows retrieving the Http endpoint where Keycloak can be accessed.
+func (container *KeycloakContainer) HttpEndpoint(ctx context.Context) (string, error) {
+	ip, err := container.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := container.MappedPort(ctx, DefaultHTTPPort)
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + net.JoinHostPort(ip, port.Port()), nil
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package keycloak_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/testcontainers-go/ensemble/keycloak"
+)
+
+func TestReadRealm(t *testing.T) {
+	realm, err := keycloak.ReadRealm("microcks-realm.json")
+	require.NoError(t, err)
+	require.Equal(t, keycloak.DefaultRealm, realm.Name)
+	require.Equal(t, keycloak.DefaultServiceAccountCredentials, realm.ServiceAccountCredentials)
+
+	_, err = keycloak.ReadRealm("missing-realm.json")
+	require.Error(t, err)
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package localstack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/redact"
+	"microcks.io/testcontainers-go/internal/registry"
+)
+
+const (
+	// DefaultImage represents the default LocalStack image.
+	DefaultImage = "localstack/localstack:3.4"
+
+	// DefaultPort represents the default LocalStack edge port.
+	DefaultPort = "4566/tcp"
+
+	// DefaultNetworkAlias represents the default network alias of the the LocalStackContainer.
+	DefaultNetworkAlias = "localstack"
+
+	// DefaultRegion represents the default region of LocalStack.
+	DefaultRegion = "us-east-1"
+
+	// DefaultAccessKey represents the access key accepted by LocalStack.
+	DefaultAccessKey = "test"
+
+	// DefaultSecretKey represents the secret key accepted by LocalStack.
+	DefaultSecretKey = "test"
+)
+
+// LocalStackContainer represents the LocalStack container type used in the ensemble.
+type LocalStackContainer struct {
+	testcontainers.Container
+}
+
+// RunContainer runs the LocalStack container, emulating SQS and SNS by default.
+// Deprecated: use Run instead.
+func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*LocalStackContainer, error) {
+	return Run(ctx, DefaultImage, opts...)
+}
+
+// Run runs the LocalStack container, emulating SQS and SNS by default, using given image.
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*LocalStackContainer, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        img,
+			ExposedPorts: []string{DefaultPort},
+			Env: map[string]string{
+				"SERVICES":       "sqs,sns",
+				"DEFAULT_REGION": DefaultRegion,
+			},
+			WaitingFor: wait.ForLog("Ready."),
+		},
+		Started: true,
+	}
+
+	for _, opt := range opts {
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error starting LocalStack container (%s): %w", redact.Request(req), err)
+	}
+
+	return &LocalStackContainer{Container: container}, nil
+}
+
+// WithServices allows to choose the emulated AWS services, e.g. "sqs", "sns".
+func WithServices(services ...string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		req.Env["SERVICES"] = strings.Join(services, ",")
+
+		return nil
+	}
+}
+
+// WithLabels adds labels to the LocalStack container, e.g. for cleanup policies or cost attribution.
+func WithLabels(labels map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		for key, value := range labels {
+			req.Labels[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithLifecycleHooks adds hooks to the LocalStack container lifecycle, e.g. to run initialization once it is started
+// or capture logs before it is terminated.
+func WithLifecycleHooks(hooks ...testcontainers.ContainerLifecycleHooks) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, hooks...)
+
+		return nil
+	}
+}
+
+// WithRequestModifier modifies the LocalStack container request with given function, e.g. to tweak settings not
+// covered by the options of the package. It is applied in order with other options.
+func WithRequestModifier(modifier func(*testcontainers.GenericContainerRequest)) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		modifier(req)
+		return nil
+	}
+}
+
+// Endpoint allows retrieving the endpoint where LocalStack can be accessed, to be used as AWS endpoint override
+// by the application under test.
+func (container *LocalStackContainer) Endpoint(ctx context.Context) (string, error) {
+	ip, err := container.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := container.MappedPort(ctx, DefaultPort)
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + net.JoinHostPort(ip, port.Port()), nil
+}
+
+// CreateQueue creates a SQS queue and returns its URL.
+func (container *LocalStackContainer) CreateQueue(ctx context.Context, name string) (string, error) {
+	var output struct {
+		QueueUrl string
+	}
+	if err := container.awslocal(ctx, &output, "sqs", "create-queue", "--queue-name", name); err != nil {
+		return "", err
+	}
+
+	return output.QueueUrl, nil
+}
+
+// CreateTopic creates a SNS topic and returns its ARN.
+func (container *LocalStackContainer) CreateTopic(ctx context.Context, name string) (string, error) {
+	var output struct {
+		TopicArn string
+	}
+	if err := container.awslocal(ctx, &output, "sns", "create-topic", "--name", name); err != nil {
+		return "", err
+	}
+
+	return output.TopicArn, nil
+}
+
+// Subscribe subscribes a SQS queue, given by its name, to a SNS topic and returns the subscription ARN.
+func (container *LocalStackContainer) Subscribe(ctx context.Context, topicArn, queueName string) (string, error) {
+	queueArn := fmt.Sprintf("arn:aws:sqs:%s:000000000000:%s", DefaultRegion, queueName)
+
+	var output struct {
+		SubscriptionArn string
+	}
+	if err := container.awslocal(ctx, &output, "sns", "subscribe", "--topic-arn", topicArn, "--protocol", "sqs", "--notification-endpoint", queueArn); err != nil {
+		return "", err
+	}
+
+	return output.SubscriptionArn, nil
+}
+
+// awslocal runs an AWS CLI command within the container and decodes its JSON output.
+func (container *LocalStackContainer) awslocal(ctx context.Context, output any, args ...string) error {
+	cmd := append([]string{"awslocal", "--output", "json"}, args...)
+	code, reader, err := container.Exec(ctx, cmd, tcexec.Multiplexed())
+	if err != nil {
+		return fmt.Errorf("error running %s: %w", strings.Join(args[:2], " "), err)
+	}
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("error reading %s output: %w", strings.Join(args[:2], " "), err)
+	}
+	if code != 0 {
+		return fmt.Errorf("unable to run %s, bad exit code, actual %d, expected 0: %s", strings.Join(args[:2], " "), code, out)
+	}
+
+	if err := json.Unmarshal(out, output); err != nil {
+		return fmt.Errorf("error decoding %s output: %w", strings.Join(args[:2], " "), err)
+	}
+	return nil
+}
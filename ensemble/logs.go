@@ -0,0 +1,168 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Member represents the name of an ensemble member.
+type Member string
+
+const (
+	// MemberMicrocks is the Microcks member.
+	MemberMicrocks Member = "microcks"
+	// MemberAsyncMinion is the Async Minion member.
+	MemberAsyncMinion Member = "minion"
+	// MemberPostman is the Postman runtime member.
+	MemberPostman Member = "postman"
+	// MemberKeycloak is the Keycloak member.
+	MemberKeycloak Member = "keycloak"
+	// MemberKafka is the Kafka broker member.
+	MemberKafka Member = "kafka"
+	// MemberMQTTBroker is the MQTT broker member.
+	MemberMQTTBroker Member = "mqtt"
+	// MemberAMQPBroker is the AMQP broker member.
+	MemberAMQPBroker Member = "amqp"
+	// MemberLocalStack is the LocalStack member.
+	MemberLocalStack Member = "localstack"
+	// MemberGooglePubSubEmulator is the Google Pub/Sub emulator member.
+	MemberGooglePubSubEmulator Member = "pubsub"
+	// MemberApp is the application under test.
+	MemberApp Member = "app"
+)
+
+// member represents a started ensemble member with its name.
+type member struct {
+	name      Member
+	container testcontainers.Container
+}
+
+// members returns the started ensemble members.
+func (ec *MicrocksContainersEnsemble) members() []member {
+	var members []member
+	add := func(name Member, container testcontainers.Container, started bool) {
+		if started {
+			members = append(members, member{name, container})
+		}
+	}
+
+	add(MemberMicrocks, ec.microcksContainer, ec.microcksContainer != nil && !ec.microcksContainer.IsRemote())
+	add(MemberAsyncMinion, ec.asyncMinionContainer, ec.asyncMinionContainer != nil)
+	add(MemberPostman, ec.postmanContainer, ec.postmanContainer != nil)
+	add(MemberKeycloak, ec.keycloakContainer, ec.keycloakContainer != nil)
+	add(MemberKafka, ec.kafkaContainer, ec.kafkaContainer != nil)
+	add(MemberMQTTBroker, ec.mqttContainer, ec.mqttContainer != nil)
+	add(MemberAMQPBroker, ec.amqpContainer, ec.amqpContainer != nil)
+	if ec.localStackContainer != nil {
+		add(MemberLocalStack, ec.localStackContainer.Container, true)
+	}
+	add(MemberGooglePubSubEmulator, ec.googlePubSubContainer, ec.googlePubSubContainer != nil)
+	add(MemberApp, ec.appContainer, ec.appContainer != nil)
+
+	return members
+}
+
+// optionsOf returns the container options of given member.
+func (ec *MicrocksContainersEnsemble) optionsOf(name Member) (*ContainerOptions, error) {
+	switch name {
+	case MemberMicrocks:
+		return &ec.microcksContainerOptions, nil
+	case MemberAsyncMinion:
+		return &ec.asyncMinionContainerOptions, nil
+	case MemberPostman:
+		return &ec.postmanContainerOptions, nil
+	case MemberKeycloak:
+		return &ec.keycloakContainerOptions, nil
+	case MemberKafka:
+		return &ec.kafkaContainerOptions, nil
+	case MemberMQTTBroker:
+		return &ec.mqttContainerOptions, nil
+	case MemberAMQPBroker:
+		return &ec.amqpContainerOptions, nil
+	case MemberLocalStack:
+		return &ec.localStackContainerOptions, nil
+	case MemberGooglePubSubEmulator:
+		return &ec.googlePubSubContainerOptions, nil
+	}
+	return nil, fmt.Errorf("error configuring ensemble: unknown member %s", name)
+}
+
+// Logs returns the logs of all ensemble members, interleaved line by line and prefixed with the member name,
+// e.g. "[microcks] ...", "[minion] ...", "[kafka] ...". Errors retrieving logs are returned when reading.
+func (ec *MicrocksContainersEnsemble) Logs(ctx context.Context) io.Reader {
+	reader, writer := io.Pipe()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	for _, m := range ec.members() {
+		wg.Add(1)
+		go func(m member) {
+			defer wg.Done()
+			if err := m.copyLogs(ctx, writer, &mu); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(m)
+	}
+
+	// Unblock members writing to the pipe when ctx is done, even if logs are not read anymore.
+	stop := context.AfterFunc(ctx, func() {
+		writer.CloseWithError(ctx.Err())
+	})
+	go func() {
+		wg.Wait()
+		stop()
+		writer.CloseWithError(errors.Join(errs...))
+	}()
+
+	return reader
+}
+
+// copyLogs writes member logs to w, each line prefixed with member name and written while holding mu.
+func (m member) copyLogs(ctx context.Context, w io.Writer, mu *sync.Mutex) error {
+	logs, err := m.container.Logs(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving %s logs: %w", m.name, err)
+	}
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		mu.Lock()
+		_, err = fmt.Fprintf(w, "[%s] %s\n", m.name, scanner.Text())
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading %s logs: %w", m.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/testcontainers/testcontainers-go"
+	microcks "microcks.io/testcontainers-go"
+	"microcks.io/testcontainers-go/ensemble/async"
+	"microcks.io/testcontainers-go/ensemble/keycloak"
+	"microcks.io/testcontainers-go/ensemble/localstack"
+	"microcks.io/testcontainers-go/ensemble/postman"
+)
+
+// WithStaticHostResolution adds the network aliases of started members to the hosts file of members started after
+// them, so that Microcks, the Async Minion and the application under test reach their dependencies without relying
+// on the network DNS. It is required by container engines without name resolution on user networks, e.g. Podman
+// with CNI networking and no dnsname plugin. Members started concurrently still rely on the network DNS.
+func WithStaticHostResolution() Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.staticHostResolution = true
+		return nil
+	}
+}
+
+// addStaticHosts adds the network aliases of started members to the hosts file of the member with given options,
+// when static host resolution is enabled.
+func (ec *MicrocksContainersEnsemble) addStaticHosts(ctx context.Context, options *ContainerOptions) error {
+	if !ec.staticHostResolution {
+		return nil
+	}
+	opt, err := ec.staticHosts(ctx)
+	if err != nil {
+		return err
+	}
+	options.Add(opt)
+	return nil
+}
+
+// staticHosts returns an option adding the network aliases of started members to the container hosts file.
+func (ec *MicrocksContainersEnsemble) staticHosts(ctx context.Context) (testcontainers.CustomizeRequestOption, error) {
+	var hosts []string
+	for _, m := range ec.members() {
+		alias := aliasOf(m.name)
+		if alias == "" {
+			continue
+		}
+		inspect, err := m.container.Inspect(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error inspecting %s: %w", m.name, err)
+		}
+		settings, ok := inspect.NetworkSettings.Networks[ec.network.Name]
+		if !ok || settings.IPAddress == "" {
+			return nil, fmt.Errorf("error resolving %s: no address on network %s", m.name, ec.network.Name)
+		}
+		hosts = append(hosts, alias+":"+settings.IPAddress)
+	}
+
+	return func(req *testcontainers.GenericContainerRequest) error {
+		previous := req.HostConfigModifier
+		req.HostConfigModifier = func(hostConfig *container.HostConfig) {
+			if previous != nil {
+				previous(hostConfig)
+			}
+			hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, hosts...)
+		}
+		return nil
+	}, nil
+}
+
+// aliasOf returns the network alias of given member, empty for the application under test.
+func aliasOf(name Member) string {
+	switch name {
+	case MemberMicrocks:
+		return microcks.DefaultNetworkAlias
+	case MemberAsyncMinion:
+		return async.DefaultNetworkAlias
+	case MemberPostman:
+		return postman.DefaultNetworkAlias
+	case MemberKeycloak:
+		return keycloak.DefaultNetworkAlias
+	case MemberKafka:
+		return KafkaNetworkAlias
+	case MemberMQTTBroker:
+		return MQTTNetworkAlias
+	case MemberAMQPBroker:
+		return AMQPNetworkAlias
+	case MemberLocalStack:
+		return localstack.DefaultNetworkAlias
+	case MemberGooglePubSubEmulator:
+		return GooglePubSubNetworkAlias
+	}
+	return ""
+}
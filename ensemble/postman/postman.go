@@ -17,13 +17,23 @@ package postman
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net"
 
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/logs"
+	"microcks.io/testcontainers-go/internal/proxy"
+	"microcks.io/testcontainers-go/internal/redact"
+	"microcks.io/testcontainers-go/internal/registry"
 )
 
 const (
-	defaultImage = "quay.io/microcks/microcks-postman-runtime:latest"
+	// DefaultImage represents the default Postman image.
+	DefaultImage = "quay.io/microcks/microcks-postman-runtime:latest"
 
 	// DefaultHTTPPort represents the default Postman HTTP port.
 	DefaultHTTPPort = "3000/tcp"
@@ -38,10 +48,16 @@ type PostmanContainer struct {
 }
 
 // RunContainer runs the Postman container.
+// Deprecated: use Run instead.
 func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*PostmanContainer, error) {
+	return Run(ctx, DefaultImage, opts...)
+}
+
+// Run runs the Postman container, using given image.
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*PostmanContainer, error) {
 	req := testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
-			Image:        defaultImage,
+			Image:        img,
 			ExposedPorts: []string{DefaultHTTPPort},
 			WaitingFor:   wait.ForLog("Microcks postman-runtime wrapper listening on port: 3000"),
 		},
@@ -49,19 +65,30 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 	}
 
 	for _, opt := range opts {
-		opt.Customize(&req)
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Apply(&req); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&req); err != nil {
+		return nil, err
 	}
 
 	container, err := testcontainers.GenericContainer(ctx, req)
+	if container != nil {
+		cleanup.TrackContainer(container)
+	}
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error starting Postman container (%s): %w", redact.Request(req), err)
 	}
 
 	return &PostmanContainer{Container: container}, nil
 }
 
 // WithNetwork allows to add a custom network.
-// Deprecated: Use network.WithNetwork from testcontainers instead.
+// Deprecated: Use WithDockerNetwork or network.WithNetwork from testcontainers instead.
 func WithNetwork(networkName string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		req.Networks = append(req.Networks, networkName)
@@ -70,8 +97,17 @@ func WithNetwork(networkName string) testcontainers.CustomizeRequestOption {
 	}
 }
 
+// WithDockerNetwork attaches the Postman container to given network, as created by the testcontainers network
+// package, with given aliases or DefaultNetworkAlias when none is provided.
+func WithDockerNetwork(nw *testcontainers.DockerNetwork, aliases ...string) testcontainers.CustomizeRequestOption {
+	if len(aliases) == 0 {
+		aliases = []string{DefaultNetworkAlias}
+	}
+	return network.WithNetwork(aliases, nw)
+}
+
 // WithNetworkAlias allows to add a custom network alias for a specific network.
-// Deprecated: Use network.WithNetwork from testcontainers instead.
+// Deprecated: Use WithDockerNetwork or network.WithNetwork from testcontainers instead.
 func WithNetworkAlias(networkName, networkAlias string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		if req.NetworkAliases == nil {
@@ -82,3 +118,71 @@ func WithNetworkAlias(networkName, networkAlias string) testcontainers.Customize
 		return nil
 	}
 }
+
+// WithLabels adds labels to the Postman container, e.g. for cleanup policies or cost attribution.
+func WithLabels(labels map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		for key, value := range labels {
+			req.Labels[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithLifecycleHooks adds hooks to the Postman container lifecycle, e.g. to run initialization once it is started
+// or capture logs before it is terminated.
+func WithLifecycleHooks(hooks ...testcontainers.ContainerLifecycleHooks) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, hooks...)
+
+		return nil
+	}
+}
+
+// WithRequestModifier modifies the Postman container request with given function, e.g. to tweak settings not
+// covered by the options of the package. It is applied in order with other options.
+func WithRequestModifier(modifier func(*testcontainers.GenericContainerRequest)) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		modifier(req)
+		return nil
+	}
+}
+
+// WithProxy allows to route outgoing traffic through a corporate proxy.
+func WithProxy(httpProxy, httpsProxy, noProxy string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		for key, value := range proxy.Env(httpProxy, httpsProxy, noProxy, false) {
+			req.Env[key] = value
+		}
+
+		return nil
+	}
+}
+
+// HttpEndpoint allows retrieving the Http endpoint where the Postman runtime can be accessed.
+func (container *PostmanContainer) HttpEndpoint(ctx context.Context) (string, error) {
+	ip, err := container.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := container.MappedPort(ctx, DefaultHTTPPort)
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + net.JoinHostPort(ip, port.Port()), nil
+}
+
+// FollowLogs streams the Postman container logs to w, from its start and until ctx is done, e.g. to watch
+// contract tests live. It blocks, so it is usually run in its own goroutine.
+func (container *PostmanContainer) FollowLogs(ctx context.Context, w io.Writer) error {
+	return logs.Follow(ctx, container.GetContainerID(), w)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"strconv"
+	"time"
+
+	"microcks.io/testcontainers-go/ensemble/async"
+)
+
+const (
+	// PresetMicrocksImage represents the Microcks image pinned by presets.
+	PresetMicrocksImage = "quay.io/microcks/microcks-uber:1.9.1"
+	// PresetAsyncMinionImage represents the Async Minion image pinned by presets.
+	PresetAsyncMinionImage = "quay.io/microcks/microcks-uber-async-minion:1.9.1"
+	// PresetPostmanImage represents the Postman runtime image pinned by presets.
+	PresetPostmanImage = "quay.io/microcks/microcks-postman-runtime:0.6.0"
+
+	// presetReadinessTimeout represents the time presets wait for members to be ready.
+	presetReadinessTimeout = time.Minute
+	// presetAsyncFrequency represents the mock messages publication frequency of presets, in seconds.
+	presetAsyncFrequency = 3
+)
+
+// Minimal is a preset starting Microcks only, with a pinned image, waiting for it to be ready.
+// Options given after it override its settings.
+func Minimal() Option {
+	return withPreset(
+		WithMicrocksImage(PresetMicrocksImage),
+		WithReadinessTimeout(presetReadinessTimeout),
+	)
+}
+
+// Full is a preset starting Microcks with Postman and the Async Feature, with pinned images, publishing mock
+// messages every 3 seconds and waiting for all members to be ready.
+// Options given after it override its settings.
+func Full() Option {
+	return withPreset(
+		Minimal(),
+		WithPostmanImage(PresetPostmanImage),
+		WithAsyncFeatureImage(PresetAsyncMinionImage),
+		WithAsyncDefaultFrequency(presetAsyncFrequency),
+	)
+}
+
+// AsyncKafka is a preset starting Microcks and the Async Feature connected to a Kafka broker, with pinned images,
+// publishing mock messages every 3 seconds and waiting for all members to be ready.
+// Options given after it override its settings.
+func AsyncKafka() Option {
+	return withPreset(
+		Minimal(),
+		WithAsyncFeatureImage(PresetAsyncMinionImage),
+		WithKafka(),
+		WithAsyncDefaultFrequency(presetAsyncFrequency),
+	)
+}
+
+// WithAsyncDefaultFrequency sets the frequency, in seconds, at which the Async Feature publishes mock messages
+// for operations not defining their own.
+func WithAsyncDefaultFrequency(seconds int) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.asyncMinionContainerOptions.Add(async.WithEnv("MINION_DEFAULT_FREQUENCY", strconv.Itoa(seconds)))
+		e.minionCustomized = true
+		return nil
+	}
+}
+
+// WithReadinessTimeout makes RunContainers wait for all members to be ready, using WaitReady with given timeout.
+func WithReadinessTimeout(timeout time.Duration) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		e.readinessTimeout = timeout
+		return nil
+	}
+}
+
+func withPreset(opts ...Option) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		for _, opt := range opts {
+			if err := opt(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
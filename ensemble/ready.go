@@ -0,0 +1,187 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"microcks.io/testcontainers-go/ensemble/async"
+	"microcks.io/testcontainers-go/ensemble/keycloak"
+	"microcks.io/testcontainers-go/ensemble/localstack"
+	"microcks.io/testcontainers-go/ensemble/postman"
+	"microcks.io/testcontainers-go/health"
+)
+
+// readinessPollInterval represents the interval between two readiness checks of a member.
+const readinessPollInterval = 200 * time.Millisecond
+
+// readinessCheck represents the check telling if an ensemble member is ready.
+type readinessCheck struct {
+	member string
+	check  func(ctx context.Context) error
+}
+
+// WaitReady waits for every ensemble member to be ready: Microcks and Async Minion health endpoints, Keycloak realm
+// and brokers ports. It returns an error naming the members that are not ready once timeout elapsed.
+func (ec *MicrocksContainersEnsemble) WaitReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	checks := ec.readinessChecks()
+	errs := make([]error, len(checks))
+	done := make(chan int)
+	for i, c := range checks {
+		go func(i int, c readinessCheck) {
+			errs[i] = waitFor(ctx, c)
+			done <- i
+		}(i, c)
+	}
+	for range checks {
+		<-done
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ec *MicrocksContainersEnsemble) readinessChecks() []readinessCheck {
+	var checks []readinessCheck
+
+	if ec.microcksContainer != nil {
+		checks = append(checks, healthCheck("microcks", ec.microcksContainer.Health))
+	}
+	if ec.asyncMinionContainer != nil {
+		checks = append(checks, healthCheck(async.DefaultNetworkAlias, ec.asyncMinionContainer.Health))
+	}
+	if ec.keycloakContainer != nil {
+		checks = append(checks, readinessCheck{keycloak.DefaultNetworkAlias, func(ctx context.Context) error {
+			endpoint, err := ec.keycloakContainer.HttpEndpoint(ctx)
+			if err != nil {
+				return err
+			}
+			return httpCheck(ctx, http.DefaultClient, endpoint+"/realms/"+keycloak.DefaultRealm)
+		}})
+	}
+	if ec.postmanContainer != nil {
+		checks = append(checks, tcpCheck(postman.DefaultNetworkAlias, ec.postmanContainer, postman.DefaultHTTPPort))
+	}
+	if ec.kafkaContainer != nil {
+		checks = append(checks, tcpCheck(KafkaNetworkAlias, ec.kafkaContainer, "9093/tcp"))
+	}
+	if ec.mqttContainer != nil {
+		checks = append(checks, tcpCheck(MQTTNetworkAlias, ec.mqttContainer, MQTTBrokerPort))
+	}
+	if ec.amqpContainer != nil {
+		checks = append(checks, tcpCheck(AMQPNetworkAlias, ec.amqpContainer, AMQPBrokerPort))
+	}
+	if ec.localStackContainer != nil {
+		checks = append(checks, readinessCheck{localstack.DefaultNetworkAlias, func(ctx context.Context) error {
+			endpoint, err := ec.localStackContainer.Endpoint(ctx)
+			if err != nil {
+				return err
+			}
+			return httpCheck(ctx, http.DefaultClient, endpoint+"/_localstack/health")
+		}})
+	}
+	if ec.googlePubSubContainer != nil {
+		checks = append(checks, tcpCheck(GooglePubSubNetworkAlias, ec.googlePubSubContainer, GooglePubSubPort))
+	}
+
+	return checks
+}
+
+// waitFor polls a readiness check until it succeeds or ctx is done.
+func waitFor(ctx context.Context, c readinessCheck) error {
+	for {
+		err := c.check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("member %s is not ready: %w", c.member, err)
+		case <-time.After(readinessPollInterval):
+		}
+	}
+}
+
+func healthCheck(member string, get func(ctx context.Context) (*health.Status, error)) readinessCheck {
+	return readinessCheck{member, func(ctx context.Context) error {
+		status, err := get(ctx)
+		if err != nil {
+			return err
+		}
+		if !status.IsUp() {
+			return fmt.Errorf("health status is %s", status.Status)
+		}
+		return nil
+	}}
+}
+
+func httpCheck(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to check health, bad status code, actual %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+	return nil
+}
+
+func tcpCheck(member string, container testcontainers.Container, port nat.Port) readinessCheck {
+	return readinessCheck{member, func(ctx context.Context) error {
+		endpoint, err := endpointOf(ctx, container, port)
+		if err != nil {
+			return err
+		}
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}}
+}
+
+// endpointOf returns the host and mapped port of a container port.
+func endpointOf(ctx context.Context, container testcontainers.Container, port nat.Port) (string, error) {
+	host, err := container.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	mapped, err := container.MappedPort(ctx, port)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, mapped.Port()), nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// restartStopTimeout represents the time given to a member to stop gracefully before being killed.
+const restartStopTimeout = 10 * time.Second
+
+// Restart stops and starts again a single ensemble member, e.g. MemberKafka or MemberAsyncMinion, so that
+// resilience scenarios can be tested. The member keeps its network aliases, but its mapped ports may change:
+// endpoints have to be retrieved again after a restart.
+func (ec *MicrocksContainersEnsemble) Restart(ctx context.Context, name Member) error {
+	for _, m := range ec.members() {
+		if m.name != name {
+			continue
+		}
+
+		timeout := restartStopTimeout
+		if err := m.container.Stop(ctx, &timeout); err != nil {
+			return fmt.Errorf("error stopping %s: %w", name, err)
+		}
+		if err := m.container.Start(ctx); err != nil {
+			return fmt.Errorf("error starting %s: %w", name, err)
+		}
+		if name == MemberMicrocks {
+			// Microcks content may not survive the restart.
+			ec.microcksContainer.InvalidateServiceCache()
+		}
+		return nil
+	}
+
+	return fmt.Errorf("error restarting %s: member not started in the ensemble", name)
+}
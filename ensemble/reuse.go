@@ -0,0 +1,95 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+	microcks "microcks.io/testcontainers-go"
+	"microcks.io/testcontainers-go/ensemble/async"
+	"microcks.io/testcontainers-go/ensemble/keycloak"
+	"microcks.io/testcontainers-go/ensemble/localstack"
+	"microcks.io/testcontainers-go/ensemble/postman"
+)
+
+// WithReuse creates ensemble members and network with deterministic names derived from given name, and re-attaches
+// them when already running, so that test packages run by the same `go test` command share a single ensemble.
+// Such an ensemble should not be terminated by tests, its members are removed at the end of the test session.
+// All packages sharing an ensemble must use the same options.
+func WithReuse(name string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		if name == "" {
+			return fmt.Errorf("error configuring ensemble reuse: empty name")
+		}
+		e.reuseName = name
+		return nil
+	}
+}
+
+// WithReusableNetwork allows to use a network with given name, created if it doesn't exist yet.
+func WithReusableNetwork(name string) Option {
+	return func(e *MicrocksContainersEnsemble) error {
+		provider, err := testcontainers.NewDockerProvider()
+		if err != nil {
+			return fmt.Errorf("error creating Docker provider: %w", err)
+		}
+		defer provider.Close()
+
+		if resource, err := provider.GetNetwork(e.ctx, testcontainers.NetworkRequest{Name: name}); err == nil {
+			e.network = &testcontainers.DockerNetwork{ID: resource.ID, Name: resource.Name, Driver: resource.Driver}
+			return networkOptionApply(e)
+		}
+
+		nw, err := provider.CreateNetwork(e.ctx, testcontainers.NetworkRequest{
+			Name:           name,
+			CheckDuplicate: true,
+			Labels:         e.networkLabels(),
+		})
+		if err != nil {
+			return fmt.Errorf("error creating network %s: %w", name, err)
+		}
+		e.network = nw.(*testcontainers.DockerNetwork)
+		return networkOptionApply(e)
+	}
+}
+
+// memberOptions returns the container options of every member, by network alias.
+func (ec *MicrocksContainersEnsemble) memberOptions() map[string]*ContainerOptions {
+	return map[string]*ContainerOptions{
+		microcks.DefaultNetworkAlias:   &ec.microcksContainerOptions,
+		async.DefaultNetworkAlias:      &ec.asyncMinionContainerOptions,
+		postman.DefaultNetworkAlias:    &ec.postmanContainerOptions,
+		keycloak.DefaultNetworkAlias:   &ec.keycloakContainerOptions,
+		KafkaNetworkAlias:              &ec.kafkaContainerOptions,
+		MQTTNetworkAlias:               &ec.mqttContainerOptions,
+		AMQPNetworkAlias:               &ec.amqpContainerOptions,
+		localstack.DefaultNetworkAlias: &ec.localStackContainerOptions,
+		GooglePubSubNetworkAlias:       &ec.googlePubSubContainerOptions,
+	}
+}
+
+// applyReuse names members after the reuse name, and enables their reuse.
+func (ec *MicrocksContainersEnsemble) applyReuse() {
+	for alias, options := range ec.memberOptions() {
+		name := ec.reuseName + "-" + alias
+		options.Add(testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+			req.Name = name
+			req.Reuse = true
+			return nil
+		}))
+	}
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"bytes"
+	"context"
+)
+
+// Snapshot represents a captured state of the ensemble Microcks repository.
+type Snapshot struct {
+	repository []byte
+}
+
+// Snapshot captures the state of the Microcks repository, so that expensive setup done once, e.g. in TestMain,
+// can be restored cheaply between test groups. Brokers content is not captured.
+func (ec *MicrocksContainersEnsemble) Snapshot(ctx context.Context) (*Snapshot, error) {
+	var repository bytes.Buffer
+	if err := ec.microcksContainer.ExportSnapshot(ctx, &repository); err != nil {
+		return nil, err
+	}
+	return &Snapshot{repository: repository.Bytes()}, nil
+}
+
+// Restore restores the Microcks repository to the state captured by given snapshot, removing services imported
+// since then.
+func (ec *MicrocksContainersEnsemble) Restore(ctx context.Context, snapshot *Snapshot) error {
+	return ec.microcksContainer.RestoreSnapshot(ctx, bytes.NewReader(snapshot.repository))
+}
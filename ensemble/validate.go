@@ -0,0 +1,50 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ensemble
+
+import (
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// validate returns the errors of options combinations that cannot work, before any member is started.
+func (ec *MicrocksContainersEnsemble) validate() []error {
+	var errs []error
+	if ec.remoteMicrocksEndpoint != "" && (ec.asyncEnabled || ec.postmanEnabled || ec.keycloakEnabled) {
+		errs = append(errs, fmt.Errorf("error configuring ensemble: Async, Postman and Keycloak features need a local Microcks container"))
+	}
+	if ec.minionCustomized && !ec.asyncEnabled {
+		errs = append(errs, fmt.Errorf("error configuring ensemble: Async Minion options and broker connections need the Async feature, see WithAsyncFeature"))
+	}
+	if ec.postmanCustomized && !ec.postmanEnabled {
+		errs = append(errs, fmt.Errorf("error configuring ensemble: Postman options need the Postman container, see WithPostman"))
+	}
+	if ec.keycloakCustomized && !ec.keycloakEnabled {
+		errs = append(errs, fmt.Errorf("error configuring ensemble: Keycloak options need the Keycloak feature, see WithKeycloakFeature"))
+	}
+
+	// Async Minion options, e.g. broker connections, are checked now rather than once Microcks is started.
+	if ec.asyncEnabled {
+		req := testcontainers.GenericContainerRequest{}
+		for _, opt := range ec.asyncMinionContainerOptions.list {
+			if err := opt.Customize(&req); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
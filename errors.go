@@ -0,0 +1,38 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"errors"
+
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+var (
+	// ErrArtifactRejected is matched by errors of artifact or snapshot imports that Microcks rejected, e.g. as
+	// invalid or not supported.
+	ErrArtifactRejected = apiclient.ErrArtifactRejected
+
+	// ErrServiceNotFound is matched by errors of calls about a Service that Microcks does not hold.
+	ErrServiceNotFound = apiclient.ErrServiceNotFound
+
+	// ErrAuthRequired is matched by errors of calls that Microcks, or its Keycloak, rejected as unauthenticated or
+	// unauthorized, e.g. because of a missing or wrong service account.
+	ErrAuthRequired = apiclient.ErrAuthRequired
+
+	// ErrTestTimeout is matched by errors of contract tests still in progress when their timeout elapsed.
+	ErrTestTimeout = errors.New("test timed out")
+)
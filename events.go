@@ -0,0 +1,101 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"time"
+
+	"microcks.io/go-client"
+)
+
+// eventsBufferSize represents the number of events kept until they are received. Events are dropped when the
+// buffer is full, so that the module never blocks on a slow receiver.
+const eventsBufferSize = 64
+
+// EventType represents the type of a lifecycle Event.
+type EventType string
+
+const (
+	// EventContainerStarted is emitted once the Microcks container is started, before artifacts are imported.
+	EventContainerStarted EventType = "ContainerStarted"
+	// EventArtifactImported is emitted once an artifact, remote artifact or snapshot is imported.
+	EventArtifactImported EventType = "ArtifactImported"
+	// EventTestLaunched is emitted once a contract test is launched by TestEndpoint.
+	EventTestLaunched EventType = "TestLaunched"
+	// EventTestFinished is emitted once a contract test launched by TestEndpoint is finished.
+	EventTestFinished EventType = "TestFinished"
+	// EventInvocationsReached is emitted once WaitForInvocations observed the expected number of invocations.
+	EventInvocationsReached EventType = "InvocationsReached"
+)
+
+// Event represents a structured lifecycle event of the module, e.g. to build progress UIs or diagnostics.
+type Event struct {
+	// Type represents the event type.
+	Type EventType
+
+	// Time represents the time the event occurred.
+	Time time.Time
+
+	// Subject represents what the event is about: the imported artifact path or URL, the tested or invoked
+	// Service as "name:version", empty for container events.
+	Subject string
+
+	// TestResult represents the result of a finished test, nil for other events.
+	TestResult *client.TestResult
+}
+
+// Events returns the channel of lifecycle events of the container. Events emitted before the first call, e.g.
+// EventContainerStarted, are buffered. The channel is closed once the container is terminated.
+func (container *MicrocksContainer) Events() <-chan Event {
+	container.eventsMutex.Lock()
+	defer container.eventsMutex.Unlock()
+
+	return container.eventsChannel()
+}
+
+// emit sends an event of given type, without blocking when the events buffer is full.
+func (container *MicrocksContainer) emit(event Event) {
+	container.eventsMutex.Lock()
+	defer container.eventsMutex.Unlock()
+
+	if container.eventsClosed {
+		return
+	}
+	event.Time = time.Now()
+	select {
+	case container.eventsChannel() <- event:
+	default:
+	}
+}
+
+// closeEvents closes the events channel.
+func (container *MicrocksContainer) closeEvents() {
+	container.eventsMutex.Lock()
+	defer container.eventsMutex.Unlock()
+
+	if !container.eventsClosed {
+		close(container.eventsChannel())
+		container.eventsClosed = true
+	}
+}
+
+// eventsChannel returns the events channel, created on first use. eventsMutex must be held.
+func (container *MicrocksContainer) eventsChannel() chan Event {
+	if container.events == nil {
+		container.events = make(chan Event, eventsBufferSize)
+	}
+	return container.events
+}
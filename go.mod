@@ -4,10 +4,13 @@ go 1.21.3
 
 require (
 	github.com/confluentinc/confluent-kafka-go/v2 v2.4.0
+	github.com/docker/docker v26.1.3+incompatible
+	github.com/docker/go-connections v0.5.0
 	github.com/gorilla/websocket v1.5.1
 	github.com/stretchr/testify v1.9.0
 	github.com/testcontainers/testcontainers-go v0.31.1-0.20240524121510-d4a21ea92ee8
 	github.com/testcontainers/testcontainers-go/modules/kafka v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 	microcks.io/go-client v0.1.0
 )
 
@@ -24,8 +27,6 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/deepmap/oapi-codegen v1.16.2 // indirect
 	github.com/distribution/reference v0.6.0 // indirect
-	github.com/docker/docker v26.1.3+incompatible // indirect
-	github.com/docker/go-connections v0.5.0 // indirect
 	github.com/docker/go-units v0.5.0 // indirect
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/getkin/kin-openapi v0.118.0 // indirect
@@ -77,5 +78,4 @@ require (
 	google.golang.org/grpc v1.64.0 // indirect
 	google.golang.org/protobuf v1.34.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,105 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	// Up represents the status of a healthy container or check.
+	Up = "UP"
+	// Down represents the status of an unhealthy container or check.
+	Down = "DOWN"
+)
+
+// Status represents the health status of a container, as reported by its health endpoint.
+type Status struct {
+	// Status represents the overall status, Up or Down.
+	Status string `json:"status"`
+
+	// Checks represents the status of individual checks, when reported (Quarkus based containers).
+	Checks []Check `json:"checks,omitempty"`
+}
+
+// Check represents the status of an individual health check.
+type Check struct {
+	// Name represents the check name.
+	Name string `json:"name"`
+
+	// Status represents the check status, Up or Down.
+	Status string `json:"status"`
+}
+
+// IsUp tells if the overall status is Up.
+func (s *Status) IsUp() bool {
+	return s != nil && s.Status == Up
+}
+
+// Get gets the health status exposed at the given URL, using the given HTTP client. Endpoints not reporting a
+// status in their body are Up when responding with 200.
+func Get(ctx context.Context, client *http.Client, healthURL string) (*Status, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating health request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting health: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading health: %w", err)
+	}
+
+	return Parse(response.StatusCode, body), nil
+}
+
+// Parse parses the health status from the status code and body of a health endpoint response.
+func Parse(statusCode int, body []byte) *Status {
+	status := &Status{}
+	if err := json.Unmarshal(bytes.TrimSpace(body), status); err != nil || status.Status == "" {
+		status = &Status{Status: Down}
+		if statusCode == http.StatusOK {
+			status.Status = Up
+		}
+	}
+
+	return status
+}
+
+// WaitStrategy returns a wait strategy waiting for the health endpoint at path on port to report an Up status,
+// e.g. to be given to microcks.WithWaitStrategy.
+func WaitStrategy(path string, port nat.Port) *wait.HTTPStrategy {
+	return wait.ForHTTP(path).
+		WithPort(port).
+		WithResponseMatcher(func(body io.Reader) bool {
+			content, err := io.ReadAll(body)
+			return err == nil && Parse(http.StatusOK, content).IsUp()
+		})
+}
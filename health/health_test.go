@@ -0,0 +1,57 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/testcontainers-go/health"
+)
+
+func TestParse(t *testing.T) {
+	status := health.Parse(http.StatusOK, nil)
+	require.True(t, status.IsUp())
+	require.Empty(t, status.Checks)
+
+	status = health.Parse(http.StatusServiceUnavailable, []byte(""))
+	require.False(t, status.IsUp())
+	require.Equal(t, health.Down, status.Status)
+
+	status = health.Parse(http.StatusServiceUnavailable, []byte(`{"status":"DOWN","checks":[{"name":"Kafka","status":"DOWN"}]}`))
+	require.False(t, status.IsUp())
+	require.Equal(t, []health.Check{{Name: "Kafka", Status: health.Down}}, status.Checks)
+}
+
+func TestGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"UP","checks":[{"name":"Microcks connection","status":"UP"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	status, err := health.Get(context.Background(), http.DefaultClient, server.URL+"/q/health")
+	require.NoError(t, err)
+	require.True(t, status.IsUp())
+	require.Len(t, status.Checks, 1)
+
+	server.Close()
+	_, err = health.Get(context.Background(), http.DefaultClient, server.URL+"/q/health")
+	require.Error(t, err)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+
+	"microcks.io/testcontainers-go/internal/redact"
+)
+
+// VersionPath represents the path of the Microcks version endpoint.
+const VersionPath = "/api/version/info"
+
+// Info represents the provenance of the Microcks instance, e.g. to record in test reports which mock engine
+// validated the build.
+type Info struct {
+	// Image represents the image reference the container was created from, e.g. "quay.io/microcks/microcks-uber:1.9.0".
+	Image string `json:"image,omitempty"`
+
+	// ImageDigest represents the resolved image digest, e.g. "quay.io/microcks/microcks-uber@sha256:...", or the
+	// image identifier when the image has no registry digest.
+	ImageDigest string `json:"imageDigest,omitempty"`
+
+	// Version represents the Microcks version, e.g. "1.9.0".
+	Version string `json:"version"`
+
+	// BuildTimestamp represents the Microcks build timestamp.
+	BuildTimestamp string `json:"buildTimestamp,omitempty"`
+
+	// Env represents the container environment, sensitive values redacted.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// Info returns the provenance of the Microcks instance: image reference and digest, Microcks version and
+// environment with sensitive values redacted. Only the version is available for a remote Microcks instance.
+func (container *MicrocksContainer) Info(ctx context.Context) (*Info, error) {
+	info := &Info{}
+	if err := container.fetchVersion(ctx, info); err != nil {
+		return nil, err
+	}
+	if container.IsRemote() {
+		return info, nil
+	}
+
+	inspect, err := container.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error inspecting Microcks container: %w", err)
+	}
+	info.Image = inspect.Config.Image
+	info.Env = redactedEnv(inspect.Config.Env)
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	image, _, err := cli.ImageInspectWithRaw(ctx, inspect.Image)
+	if err != nil {
+		return nil, fmt.Errorf("error inspecting Microcks image: %w", err)
+	}
+	info.ImageDigest = image.ID
+	if len(image.RepoDigests) > 0 {
+		info.ImageDigest = image.RepoDigests[0]
+	}
+
+	return info, nil
+}
+
+// fetchVersion fills info with the version reported by the Microcks version endpoint.
+func (container *MicrocksContainer) fetchVersion(ctx context.Context, info *Info) error {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	version, err := c.Version(ctx)
+	if err != nil {
+		return err
+	}
+	info.Version = version.VersionID
+	info.BuildTimestamp = version.BuildTimestamp
+
+	return nil
+}
+
+// redactedEnv converts a container environment, as "KEY=value" entries, to a map with sensitive values redacted.
+func redactedEnv(entries []string) map[string]string {
+	env := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, _ := strings.Cut(entry, "=")
+		env[key] = value
+	}
+	return redact.Env(env)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var (
+	mutex      sync.Mutex
+	containers = make(map[string]testcontainers.Container)
+	networks   = make(map[string]*testcontainers.DockerNetwork)
+)
+
+// Apply makes the container created from req tracked until it is terminated. TrackContainer must be called once
+// it is created.
+func Apply(req *testcontainers.GenericContainerRequest) error {
+	req.LifecycleHooks = append(req.LifecycleHooks, testcontainers.ContainerLifecycleHooks{
+		PostTerminates: []testcontainers.ContainerHook{
+			func(ctx context.Context, container testcontainers.Container) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				delete(containers, container.GetContainerID())
+				return nil
+			},
+		},
+	})
+	return nil
+}
+
+// TrackContainer tracks a container created by the module.
+func TrackContainer(container testcontainers.Container) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	containers[container.GetContainerID()] = container
+}
+
+// TrackNetwork tracks a network created by the module.
+func TrackNetwork(network *testcontainers.DockerNetwork) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	networks[network.ID] = network
+}
+
+// UntrackNetwork stops tracking a network once removed.
+func UntrackNetwork(network *testcontainers.DockerNetwork) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(networks, network.ID)
+}
+
+// All terminates every tracked container, then removes every tracked network. Errors are aggregated.
+func All(ctx context.Context) error {
+	mutex.Lock()
+	trackedContainers := make([]testcontainers.Container, 0, len(containers))
+	for _, container := range containers {
+		trackedContainers = append(trackedContainers, container)
+	}
+	trackedNetworks := make([]*testcontainers.DockerNetwork, 0, len(networks))
+	for _, network := range networks {
+		trackedNetworks = append(trackedNetworks, network)
+	}
+	mutex.Unlock()
+
+	var errs []error
+	for _, container := range trackedContainers {
+		if err := container.Terminate(ctx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// Containers without lifecycle hooks, e.g. reused ones, are untracked here.
+		mutex.Lock()
+		delete(containers, container.GetContainerID())
+		mutex.Unlock()
+	}
+	for _, network := range trackedNetworks {
+		if err := network.Remove(ctx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		UntrackNetwork(network)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+type fakeContainer struct {
+	testcontainers.Container
+	id         string
+	terminated int
+}
+
+func (c *fakeContainer) GetContainerID() string {
+	return c.id
+}
+
+func (c *fakeContainer) Terminate(ctx context.Context) error {
+	c.terminated++
+	return nil
+}
+
+func TestAll(t *testing.T) {
+	first := &fakeContainer{id: "first"}
+	second := &fakeContainer{id: "second"}
+	TrackContainer(first)
+	TrackContainer(second)
+
+	// Terminated containers are untracked by the lifecycle hook.
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, Apply(&req))
+	require.NoError(t, req.LifecycleHooks[0].PostTerminates[0](context.Background(), second))
+
+	require.NoError(t, All(context.Background()))
+	require.Equal(t, 1, first.terminated)
+	require.Equal(t, 0, second.terminated)
+
+	// Nothing is left to clean up.
+	require.NoError(t, All(context.Background()))
+	require.Equal(t, 1, first.terminated)
+}
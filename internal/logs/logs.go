@@ -0,0 +1,61 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Follow streams the logs of the container with given identifier to w, from its start and until ctx is done or the
+// container stops. Standard output and error are both written to w.
+func Follow(ctx context.Context, containerID string, w io.Writer) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	reader, err := cli.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("error following container logs: %w", err)
+	}
+	defer reader.Close()
+
+	if err := copyLogs(ctx, w, reader); err != nil {
+		return fmt.Errorf("error following container logs: %w", err)
+	}
+	return nil
+}
+
+// copyLogs demultiplexes reader to w, ignoring the error caused by ctx being done.
+func copyLogs(ctx context.Context, w io.Writer, reader io.Reader) error {
+	_, err := stdcopy.StdCopy(w, w, reader)
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package logs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyLogs(t *testing.T) {
+	var stream bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&stream, stdcopy.Stdout).Write([]byte("Started MicrocksApplication\n"))
+	require.NoError(t, err)
+	_, err = stdcopy.NewStdWriter(&stream, stdcopy.Stderr).Write([]byte("WARN something\n"))
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.NoError(t, copyLogs(context.Background(), &out, &stream))
+	require.Equal(t, "Started MicrocksApplication\nWARN something\n", out.String())
+}
+
+type cancelledReader struct {
+	ctx context.Context
+}
+
+func (r cancelledReader) Read(p []byte) (int, error) {
+	return 0, fmt.Errorf("read: %w", r.ctx.Err())
+}
+
+func TestCopyLogsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, copyLogs(ctx, io.Discard, cancelledReader{ctx}))
+	require.Error(t, copyLogs(context.Background(), io.Discard, cancelledReader{ctx}))
+}
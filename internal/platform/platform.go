@@ -0,0 +1,69 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package platform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Option returns an option running the container image for given platform, e.g. "linux/amd64", which is pulled
+// and emulated by the Docker host when it differs from the host platform.
+func Option(platform string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		parts := strings.Split(platform, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("error configuring image platform: expected os/arch[/variant], actual %q", platform)
+		}
+		req.ImagePlatform = platform
+		return nil
+	}
+}
+
+// Explain returns err, the error of starting container from req, with an explanation when it is caused by an
+// image platform that is not available or cannot be emulated by the Docker host.
+func Explain(ctx context.Context, req testcontainers.GenericContainerRequest, container testcontainers.Container, err error) error {
+	if err == nil || req.ImagePlatform == "" {
+		return err
+	}
+
+	message := err.Error()
+	if strings.Contains(message, "no matching manifest") || strings.Contains(message, "does not provide the specified platform") {
+		return fmt.Errorf("image %s is not available for platform %s: %w", req.Image, req.ImagePlatform, err)
+	}
+	if strings.Contains(message, "exec format error") || (container != nil && logsContain(ctx, container, "exec format error")) {
+		return fmt.Errorf("platform %s cannot be emulated by the Docker host, enable QEMU binfmt or Rosetta emulation: %w", req.ImagePlatform, err)
+	}
+	return err
+}
+
+func logsContain(ctx context.Context, container testcontainers.Container, text string) bool {
+	reader, err := container.Logs(ctx)
+	if err != nil {
+		return false
+	}
+	defer reader.Close()
+
+	logs, err := io.ReadAll(reader)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(logs), text)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package platform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+type fakeContainer struct {
+	testcontainers.Container
+	logs string
+}
+
+func (c *fakeContainer) Logs(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(c.logs)), nil
+}
+
+func TestOption(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, Option("linux/amd64")(&req))
+	require.Equal(t, "linux/amd64", req.ImagePlatform)
+	require.NoError(t, Option("linux/arm64/v8")(&req))
+
+	require.Error(t, Option("amd64")(&req))
+	require.Error(t, Option("linux/")(&req))
+	require.Error(t, Option("linux/arm/v7/extra")(&req))
+}
+
+func TestExplain(t *testing.T) {
+	ctx := context.Background()
+	err := errors.New("container exited with code 255")
+
+	// Without platform, errors are left untouched.
+	require.Equal(t, err, Explain(ctx, testcontainers.GenericContainerRequest{}, nil, err))
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{Image: "quay.io/microcks/microcks-uber:1.9.0", ImagePlatform: "linux/amd64"},
+	}
+	require.NoError(t, Explain(ctx, req, nil, nil))
+	require.Equal(t, err, Explain(ctx, req, &fakeContainer{logs: "Started"}, err))
+
+	explained := Explain(ctx, req, &fakeContainer{logs: "exec /usr/bin/java: exec format error"}, err)
+	require.ErrorIs(t, explained, err)
+	require.Contains(t, explained.Error(), "platform linux/amd64 cannot be emulated")
+
+	explained = Explain(ctx, req, nil, errors.New("no matching manifest for linux/amd64 in the manifest list entries"))
+	require.Contains(t, explained.Error(), "is not available for platform linux/amd64")
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proxy
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Env returns the environment variables routing outgoing traffic through the given proxies.
+// Empty values are ignored. When java is true, the equivalent JVM system properties are
+// also provided through JAVA_OPTS_APPEND, as the JVM does not read proxy environment variables.
+func Env(httpProxy, httpsProxy, noProxy string, java bool) map[string]string {
+	env := make(map[string]string)
+	set := func(key, value string) {
+		if value != "" {
+			env[strings.ToUpper(key)] = value
+			env[strings.ToLower(key)] = value
+		}
+	}
+	set("HTTP_PROXY", httpProxy)
+	set("HTTPS_PROXY", httpsProxy)
+	set("NO_PROXY", noProxy)
+
+	if java {
+		var props []string
+		props = append(props, systemProperties("http", httpProxy)...)
+		props = append(props, systemProperties("https", httpsProxy)...)
+		if hosts := nonProxyHosts(noProxy); hosts != "" {
+			props = append(props, "-Dhttp.nonProxyHosts="+hosts)
+		}
+		if len(props) > 0 {
+			env["JAVA_OPTS_APPEND"] = strings.Join(props, " ")
+		}
+	}
+
+	return env
+}
+
+func systemProperties(scheme, proxy string) []string {
+	if proxy == "" {
+		return nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+
+	props := []string{"-D" + scheme + ".proxyHost=" + u.Hostname()}
+	if port := u.Port(); port != "" {
+		props = append(props, "-D"+scheme+".proxyPort="+port)
+	}
+	return props
+}
+
+// nonProxyHosts converts a NO_PROXY list into the JVM http.nonProxyHosts format.
+func nonProxyHosts(noProxy string) string {
+	var hosts []string
+	for _, host := range strings.Split(noProxy, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if strings.HasPrefix(host, ".") {
+			host = "*" + host
+		}
+		hosts = append(hosts, host)
+	}
+
+	return strings.Join(hosts, "|")
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proxy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/testcontainers-go/internal/proxy"
+)
+
+func TestEnv(t *testing.T) {
+	env := proxy.Env("http://proxy.corp:3128", "http://proxy.corp:3129", "localhost, .internal.corp", false)
+	require.Equal(t, "http://proxy.corp:3128", env["HTTP_PROXY"])
+	require.Equal(t, "http://proxy.corp:3128", env["http_proxy"])
+	require.Equal(t, "http://proxy.corp:3129", env["HTTPS_PROXY"])
+	require.Equal(t, "localhost, .internal.corp", env["no_proxy"])
+	require.NotContains(t, env, "JAVA_OPTS_APPEND")
+}
+
+func TestEnvJava(t *testing.T) {
+	env := proxy.Env("http://proxy.corp:3128", "", "localhost,.internal.corp", true)
+	require.NotContains(t, env, "HTTPS_PROXY")
+	require.Equal(t,
+		"-Dhttp.proxyHost=proxy.corp -Dhttp.proxyPort=3128 -Dhttp.nonProxyHosts=localhost|*.internal.corp",
+		env["JAVA_OPTS_APPEND"],
+	)
+
+	require.Empty(t, proxy.Env("", "", "", true))
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package registry
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var (
+	mutex  sync.RWMutex
+	prefix string
+)
+
+// SetPrefix sets the registry prefix of the images of every container created by the module.
+func SetPrefix(registryPrefix string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	prefix = strings.TrimSuffix(registryPrefix, "/")
+}
+
+// Prefix returns the registry prefix of the images, empty when images are pulled from their original registry.
+func Prefix() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return prefix
+}
+
+// Substitutor returns the image substitutor prepending the registry prefix, if any, to image names.
+// The prefix is read when the container is created.
+func Substitutor() testcontainers.ImageSubstitutor {
+	return substitutor{}
+}
+
+// Apply makes req use the registry prefix, before its own image substitutors if any.
+func Apply(req *testcontainers.GenericContainerRequest) error {
+	req.ImageSubstitutors = append([]testcontainers.ImageSubstitutor{Substitutor()}, req.ImageSubstitutors...)
+	return nil
+}
+
+type substitutor struct{}
+
+// Description implements testcontainers.ImageSubstitutor.
+func (substitutor) Description() string {
+	return "RegistryPrefixSubstitutor (prepends the Microcks module registry prefix)"
+}
+
+// Substitute implements testcontainers.ImageSubstitutor.
+func (substitutor) Substitute(image string) (string, error) {
+	registryPrefix := Prefix()
+	if registryPrefix == "" || strings.HasPrefix(image, registryPrefix+"/") {
+		return image, nil
+	}
+	return registryPrefix + "/" + image, nil
+}
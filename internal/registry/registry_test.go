@@ -0,0 +1,41 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstitutor(t *testing.T) {
+	t.Cleanup(func() { SetPrefix("") })
+
+	image, err := Substitutor().Substitute("quay.io/microcks/microcks-uber:nightly")
+	require.NoError(t, err)
+	require.Equal(t, "quay.io/microcks/microcks-uber:nightly", image)
+
+	SetPrefix("registry.corp.example.com/mirror/")
+	require.Equal(t, "registry.corp.example.com/mirror", Prefix())
+
+	image, err = Substitutor().Substitute("quay.io/microcks/microcks-uber:nightly")
+	require.NoError(t, err)
+	require.Equal(t, "registry.corp.example.com/mirror/quay.io/microcks/microcks-uber:nightly", image)
+
+	image, err = Substitutor().Substitute("registry.corp.example.com/mirror/eclipse-mosquitto:2.0")
+	require.NoError(t, err)
+	require.Equal(t, "registry.corp.example.com/mirror/eclipse-mosquitto:2.0", image)
+}
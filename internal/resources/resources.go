@@ -0,0 +1,62 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// MemoryLimit returns an option limiting the container memory to given bytes. Swap is disabled, so that the
+// container is OOM-killed deterministically once the limit is reached.
+func MemoryLimit(bytes int64) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if bytes <= 0 {
+			return fmt.Errorf("error configuring memory limit: must be positive, actual %d", bytes)
+		}
+		modifyHostConfig(req, func(hostConfig *container.HostConfig) {
+			hostConfig.Memory = bytes
+			hostConfig.MemorySwap = bytes
+		})
+		return nil
+	}
+}
+
+// CPULimit returns an option limiting the container to given number of CPUs, e.g. 1.5.
+func CPULimit(cpus float64) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if cpus <= 0 {
+			return fmt.Errorf("error configuring CPU limit: must be positive, actual %g", cpus)
+		}
+		modifyHostConfig(req, func(hostConfig *container.HostConfig) {
+			hostConfig.NanoCPUs = int64(cpus * 1e9)
+		})
+		return nil
+	}
+}
+
+// modifyHostConfig chains modifier after the request host config modifier, if any.
+func modifyHostConfig(req *testcontainers.GenericContainerRequest, modifier func(*container.HostConfig)) {
+	previous := req.HostConfigModifier
+	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
+		if previous != nil {
+			previous(hostConfig)
+		}
+		modifier(hostConfig)
+	}
+}
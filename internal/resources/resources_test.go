@@ -0,0 +1,46 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestLimits(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			HostConfigModifier: func(hostConfig *container.HostConfig) {
+				hostConfig.Privileged = true
+			},
+		},
+	}
+	require.NoError(t, MemoryLimit(1<<30).Customize(&req))
+	require.NoError(t, CPULimit(1.5).Customize(&req))
+
+	hostConfig := &container.HostConfig{}
+	req.HostConfigModifier(hostConfig)
+	require.True(t, hostConfig.Privileged)
+	require.Equal(t, int64(1<<30), hostConfig.Memory)
+	require.Equal(t, int64(1<<30), hostConfig.MemorySwap)
+	require.Equal(t, int64(1_500_000_000), hostConfig.NanoCPUs)
+
+	require.Error(t, MemoryLimit(0).Customize(&req))
+	require.Error(t, CPULimit(-1).Customize(&req))
+}
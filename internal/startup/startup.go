@@ -0,0 +1,61 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Timeout returns strategy with given startup timeout, independent of the context used to start the container.
+func Timeout(strategy wait.Strategy, timeout time.Duration) wait.Strategy {
+	// Log strategies enforce their own default timeout, which has to be replaced.
+	if logStrategy, ok := strategy.(*wait.LogStrategy); ok {
+		return logStrategy.WithStartupTimeout(timeout)
+	}
+	return wait.ForAll(strategy).WithStartupTimeoutDefault(timeout).WithDeadline(timeout)
+}
+
+// Option returns an option applying given startup timeout to the wait strategy of the request.
+func Option(timeout time.Duration) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if timeout <= 0 {
+			return fmt.Errorf("error configuring startup timeout: must be positive, actual %s", timeout)
+		}
+		if req.WaitingFor != nil {
+			req.WaitingFor = Timeout(req.WaitingFor, timeout)
+		}
+		return nil
+	}
+}
+
+// Explain returns err, the error of starting container named name from req, with an explanation naming the
+// container when it did not become ready within its startup timeout, while ctx is still valid.
+func Explain(ctx context.Context, name string, req testcontainers.GenericContainerRequest, err error) error {
+	if err == nil || ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	if strategy, ok := req.WaitingFor.(wait.StrategyTimeout); ok && strategy.Timeout() != nil {
+		return fmt.Errorf("%s did not become ready within startup timeout of %s: %w", name, *strategy.Timeout(), err)
+	}
+	return fmt.Errorf("%s did not become ready within its startup timeout: %w", name, err)
+}
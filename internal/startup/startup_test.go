@@ -0,0 +1,67 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestTimeout(t *testing.T) {
+	strategy := Timeout(wait.ForLog("Started MicrocksApplication"), 3*time.Minute)
+	require.Equal(t, 3*time.Minute, *strategy.(wait.StrategyTimeout).Timeout())
+
+	strategy = Timeout(wait.ForHTTP("/api/health").WithPort("8080/tcp"), 3*time.Minute)
+	require.Equal(t, 3*time.Minute, *strategy.(wait.StrategyTimeout).Timeout())
+}
+
+func TestOption(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{WaitingFor: wait.ForLog("Profile prod activated")},
+	}
+	require.NoError(t, Option(2*time.Minute)(&req))
+	require.Equal(t, 2*time.Minute, *req.WaitingFor.(wait.StrategyTimeout).Timeout())
+
+	require.Error(t, Option(0)(&req))
+}
+
+func TestExplain(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			WaitingFor: Timeout(wait.ForLog("Started MicrocksApplication"), 90*time.Second),
+		},
+	}
+	timeoutErr := fmt.Errorf("wait until ready: %w", context.DeadlineExceeded)
+
+	explained := Explain(context.Background(), "Microcks container", req, timeoutErr)
+	require.ErrorIs(t, explained, context.DeadlineExceeded)
+	require.Contains(t, explained.Error(), "Microcks container did not become ready within startup timeout of 1m30s")
+
+	// Other errors and expired caller contexts are left untouched.
+	otherErr := errors.New("container exited with code 1")
+	require.Equal(t, otherErr, Explain(context.Background(), "Microcks container", req, otherErr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, timeoutErr, Explain(ctx, "Microcks container", req, timeoutErr))
+}
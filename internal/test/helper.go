@@ -35,19 +35,78 @@ import (
 	kafkaTC "github.com/testcontainers/testcontainers-go/modules/kafka"
 	"microcks.io/go-client"
 	microcks "microcks.io/testcontainers-go"
+	apiclient "microcks.io/testcontainers-go/client"
 	"microcks.io/testcontainers-go/ensemble/async"
 )
 
+// get sends a GET request bound to ctx, so that helpers honor the test deadlines.
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
 // ConfigRetrieval tests the configuration.
 func ConfigRetrieval(t *testing.T, ctx context.Context, microcksContainer *microcks.MicrocksContainer) {
 	uri, err := microcksContainer.HttpEndpoint(ctx)
 	require.NoError(t, err)
 
-	resp, err := http.Get(uri + "/api/keycloak/config")
+	resp, err := get(ctx, uri+"/api/keycloak/config")
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+// ServicesRetrieval tests the listing of imported services, operations and resources.
+func ServicesRetrieval(t *testing.T, ctx context.Context, microcksContainer *microcks.MicrocksContainer) {
+	services, err := microcksContainer.ListServices(ctx)
+	require.NoError(t, err)
+
+	var serviceID string
+	for _, s := range services {
+		if s.Name == "API Pastries" && s.Version == "0.0.1" {
+			serviceID = s.ID
+		}
+	}
+	require.NotEmpty(t, serviceID)
+
+	service, err := microcksContainer.GetService(ctx, serviceID)
+	require.NoError(t, err)
+	require.Equal(t, apiclient.ServiceTypeREST, service.Type)
+	require.NotNil(t, service.Operation("GET /pastries/{name}"))
+
+	resources, err := microcksContainer.ListResources(ctx, serviceID)
+	require.NoError(t, err)
+	require.NotEmpty(t, resources)
+
+	files, err := microcksContainer.ExportResources(ctx, "API Pastries", "0.0.1", t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, files, len(resources))
+}
+
+// KeycloakConfigRetrieval tests the Keycloak configuration.
+func KeycloakConfigRetrieval(t *testing.T, ctx context.Context, microcksContainer *microcks.MicrocksContainer, enabled bool) {
+	uri, err := microcksContainer.HttpEndpoint(ctx)
+	require.NoError(t, err)
+
+	resp, err := get(ctx, uri+"/api/keycloak/config")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	defer resp.Body.Close()
+
+	var config struct {
+		Enabled bool   `json:"enabled"`
+		Realm   string `json:"realm"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&config)
+	require.NoError(t, err)
+	require.Equal(t, enabled, config.Enabled)
+	if enabled {
+		require.Equal(t, "microcks", config.Realm)
+	}
+}
+
 // SecretRetrieval tests the secret.
 func SecretRetrieval(t *testing.T, ctx context.Context, microcksContainer *microcks.MicrocksContainer, s *client.Secret) {
 	httpEndpoint, err := microcksContainer.HttpEndpoint(ctx)
@@ -113,7 +172,19 @@ func MicrocksMockingFunctionality(t *testing.T, ctx context.Context, microcksCon
 	baseApiUrl, err := microcksContainer.RestMockEndpoint(ctx, "API Pastries", "0.0.1")
 	require.NoError(t, err)
 
-	resp, err := http.Get(baseApiUrl + "/pastries/Millefeuille")
+	// Check that mock has not been invoked yet.
+	invoked, err := microcksContainer.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.False(t, invoked)
+
+	probe, err := microcksContainer.StartProbe(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+
+	count, err := microcksContainer.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	resp, err := get(ctx, baseApiUrl+"/pastries/Millefeuille")
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -127,7 +198,7 @@ func MicrocksMockingFunctionality(t *testing.T, ctx context.Context, microcksCon
 	require.Equal(t, "Millefeuille", pastry["name"])
 
 	// Check that mock from secondary artifact has been loaded.
-	resp, err = http.Get(baseApiUrl + "/pastries/Eclair Chocolat")
+	resp, err = get(ctx, baseApiUrl+"/pastries/Eclair Chocolat")
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -139,6 +210,71 @@ func MicrocksMockingFunctionality(t *testing.T, ctx context.Context, microcksCon
 	json.Unmarshal([]byte(body), &pastry)
 
 	require.Equal(t, "Eclair Chocolat", pastry["name"])
+
+	// Check that mock has been invoked.
+	invoked, err = microcksContainer.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.True(t, invoked)
+
+	count, err = microcksContainer.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// Check that invocations can be broken down by operation.
+	operations, err := microcksContainer.OperationInvocations(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, operations["GET /pastries/{name}"])
+	require.Equal(t, 0, operations["PATCH /pastries/{name}"])
+
+	err = microcksContainer.WaitForInvocations(ctx, "API Pastries", "0.0.1", 2, time.Second)
+	require.NoError(t, err)
+
+	err = microcksContainer.WaitForInvocations(ctx, "API Pastries", "0.0.1", 3, 500*time.Millisecond)
+	require.Error(t, err)
+
+	stats, err := microcksContainer.ServiceInvocationStats(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.DailyCount)
+
+	hourlyCount := 0
+	for _, c := range stats.HourlyCount {
+		hourlyCount += c
+	}
+	require.Equal(t, 2, hourlyCount)
+
+	stats, err = microcksContainer.ServiceInvocationStatsOnDay(ctx, "API Pastries", "0.0.1", time.Now().AddDate(0, 0, -1))
+	require.NoError(t, err)
+	require.Equal(t, 0, stats.DailyCount)
+
+	delta, err := probe.Delta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, delta)
+
+	// Check that mock metrics can be exported.
+	var exported bytes.Buffer
+	err = microcksContainer.ExportMetrics(ctx, &exported)
+	require.NoError(t, err)
+
+	// Check that metrics can be scraped.
+	families, err := microcksContainer.Metrics(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, families)
+
+	// Check that received requests can be spied.
+	requests, err := microcksContainer.ReceivedRequests(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(requests))
+	require.Equal(t, http.MethodGet, requests[0].Method)
+	require.Equal(t, "/rest/API Pastries/0.0.1/pastries/Millefeuille", requests[0].URI)
+	require.Equal(t, "/rest/API Pastries/0.0.1/pastries/Eclair Chocolat", requests[1].URI)
+
+	// Check that invocation statistics can be reset.
+	err = microcksContainer.ResetInvocationStats(ctx)
+	require.NoError(t, err)
+
+	invoked, err = microcksContainer.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.False(t, invoked)
 }
 
 // MicrocksAsyncMockingFunctionality tests the Microcks async mocking functionality.
@@ -150,6 +286,10 @@ func MicrocksAsyncMockingFunctionality(t *testing.T, ctx context.Context, microc
 	}
 	expectedMessage := "{\"id\":\"4dab240d-7847-4e25-8ef3-1530687650c8\",\"customerId\":\"fe1088b3-9f30-4dc1-a93d-7b74f0a072b9\",\"status\":\"VALIDATED\",\"productQuantities\":[{\"quantity\":2,\"pastryName\":\"Croissant\"},{\"quantity\":1,\"pastryName\":\"Millefeuille\"}]}"
 
+	// Check that event mocking is active.
+	_, err = microcksAsyncMinionContainer.PublishedMessagesCount(ctx, "Pastry orders API", "0.1.0", "SUBSCRIBE pastry/orders")
+	require.NoError(t, err)
+
 	// Check signals.
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
@@ -0,0 +1,357 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sample represents a single metric sample.
+type Sample struct {
+	// Name represents the sample name (may have a _total, _count, _sum or _bucket suffix).
+	Name string
+
+	// Labels represents the sample labels.
+	Labels map[string]string
+
+	// Value represents the sample value.
+	Value float64
+}
+
+// Family represents a family of metric samples sharing the same name and type.
+type Family struct {
+	// Name represents the family name.
+	Name string
+
+	// Help represents the family help text.
+	Help string
+
+	// Type represents the family type (counter, gauge, histogram, summary or untyped).
+	Type string
+
+	// Samples represents the family samples.
+	Samples []Sample
+}
+
+// Sum returns the sum of the family samples having all the given labels.
+// Labels are given as key/value pairs.
+func (f *Family) Sum(labels ...string) float64 {
+	var sum float64
+	for _, s := range f.Samples {
+		if s.matches(labels) {
+			sum += s.Value
+		}
+	}
+
+	return sum
+}
+
+// SumBy returns the sums of the family samples having all the given labels, grouped by the value of label,
+// e.g. the published messages per protocol. Samples without label are ignored.
+func (f *Family) SumBy(label string, labels ...string) map[string]float64 {
+	sums := make(map[string]float64)
+	for _, s := range f.Samples {
+		value, ok := s.Labels[label]
+		if ok && s.matches(labels) {
+			sums[value] += s.Value
+		}
+	}
+
+	return sums
+}
+
+// Count returns the number of observations of a summary or histogram family, for samples having all the given
+// labels, e.g. the number of requests handled by a mock endpoint.
+func (f *Family) Count(labels ...string) float64 {
+	return f.sumOf(f.Name+"_count", labels)
+}
+
+// Mean returns the mean observed value of a summary or histogram family, for samples having all the given labels,
+// e.g. the mean latency of a mock endpoint. It is 0 when nothing has been observed.
+func (f *Family) Mean(labels ...string) float64 {
+	count := f.Count(labels...)
+	if count == 0 {
+		return 0
+	}
+
+	return f.sumOf(f.Name+"_sum", labels) / count
+}
+
+func (f *Family) sumOf(sampleName string, labels []string) float64 {
+	var sum float64
+	for _, s := range f.Samples {
+		if s.Name == sampleName && s.matches(labels) {
+			sum += s.Value
+		}
+	}
+
+	return sum
+}
+
+// Scrape gets and parses the metrics exposed in the Prometheus text format at the given URL.
+func Scrape(ctx context.Context, metricsURL string) (map[string]*Family, error) {
+	return ScrapeWithClient(ctx, http.DefaultClient, metricsURL)
+}
+
+// ScrapeWithClient gets and parses the metrics exposed in the Prometheus text format at the given URL,
+// using the given HTTP client.
+func ScrapeWithClient(ctx context.Context, client *http.Client, metricsURL string) (map[string]*Family, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating metrics request: %w", err)
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting metrics: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get metrics, bad status code, actual %d, expected %d", response.StatusCode, http.StatusOK)
+	}
+
+	return Parse(response.Body)
+}
+
+// Parse parses metrics exposed in the Prometheus text format.
+func Parse(r io.Reader) (map[string]*Family, error) {
+	families := make(map[string]*Family)
+	family := func(name string) *Family {
+		if f, ok := families[name]; ok {
+			return f
+		}
+		f := &Family{Name: name, Type: "untyped"}
+		families[name] = f
+		return f
+	}
+
+	var current *Family
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// Metadata or comment.
+		if strings.HasPrefix(line, "#") {
+			fields := strings.SplitN(line, " ", 4)
+			if len(fields) < 3 {
+				continue
+			}
+			switch fields[1] {
+			case "HELP":
+				current = family(fields[2])
+				if len(fields) == 4 {
+					current.Help = fields[3]
+				}
+			case "TYPE":
+				current = family(fields[2])
+				if len(fields) == 4 {
+					current.Type = fields[3]
+				}
+			}
+			continue
+		}
+
+		sample, err := parseSample(line)
+		if err != nil {
+			return nil, err
+		}
+
+		// Samples belong to the current family when their name derives from it.
+		if current != nil && current.owns(sample.Name) {
+			current.Samples = append(current.Samples, sample)
+		} else {
+			f := family(sample.Name)
+			f.Samples = append(f.Samples, sample)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading metrics: %w", err)
+	}
+
+	return families, nil
+}
+
+// Filter returns the families having at least one sample kept by the given function, with only the kept samples.
+func Filter(families map[string]*Family, keep func(f *Family, s Sample) bool) map[string]*Family {
+	filtered := make(map[string]*Family)
+	for name, f := range families {
+		var samples []Sample
+		for _, s := range f.Samples {
+			if keep(f, s) {
+				samples = append(samples, s)
+			}
+		}
+		if len(samples) > 0 {
+			filtered[name] = &Family{Name: f.Name, Help: f.Help, Type: f.Type, Samples: samples}
+		}
+	}
+
+	return filtered
+}
+
+// Write writes families in the Prometheus text format, so that they can be pushed to a Pushgateway.
+func Write(w io.Writer, families map[string]*Family) error {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := families[name]
+		if f.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, f.Help)
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			bw.WriteString(s.Name)
+			if len(s.Labels) > 0 {
+				labelNames := make([]string, 0, len(s.Labels))
+				for labelName := range s.Labels {
+					labelNames = append(labelNames, labelName)
+				}
+				sort.Strings(labelNames)
+
+				bw.WriteString("{")
+				for i, labelName := range labelNames {
+					if i > 0 {
+						bw.WriteString(",")
+					}
+					fmt.Fprintf(bw, "%s=\"%s\"", labelName, labelValueEscaper.Replace(s.Labels[labelName]))
+				}
+				bw.WriteString("}")
+			}
+			fmt.Fprintf(bw, " %s\n", strconv.FormatFloat(s.Value, 'g', -1, 64))
+		}
+	}
+
+	return bw.Flush()
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func parseSample(line string) (Sample, error) {
+	sample := Sample{Labels: make(map[string]string)}
+
+	rest := line
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		sample.Name = line[:i]
+		rest = line[i:]
+	} else {
+		return sample, fmt.Errorf("invalid metric sample: %q", line)
+	}
+
+	// Labels.
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		rest, err = parseLabels(rest[1:], sample.Labels)
+		if err != nil {
+			return sample, fmt.Errorf("invalid metric sample: %q: %w", line, err)
+		}
+	}
+
+	// Value, optionally followed by a timestamp.
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return sample, fmt.Errorf("invalid metric sample: %q", line)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return sample, fmt.Errorf("invalid metric sample value: %q: %w", line, err)
+	}
+	sample.Value = value
+
+	return sample, nil
+}
+
+func parseLabels(s string, labels map[string]string) (string, error) {
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if strings.HasPrefix(s, "}") {
+			return s[1:], nil
+		}
+
+		eq := strings.Index(s, "=")
+		if eq < 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return "", fmt.Errorf("malformed labels")
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+
+		// Read the quoted value, handling escapes.
+		var value strings.Builder
+		closed := false
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			if c == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			if c == '"' {
+				s = s[i+1:]
+				closed = true
+				break
+			}
+			value.WriteByte(c)
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated label value")
+		}
+		labels[name] = value.String()
+	}
+}
+
+func (f *Family) owns(sampleName string) bool {
+	if sampleName == f.Name {
+		return true
+	}
+	for _, suffix := range []string{"_total", "_count", "_sum", "_bucket", "_created"} {
+		if sampleName == f.Name+suffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Sample) matches(labels []string) bool {
+	for i := 0; i+1 < len(labels); i += 2 {
+		if s.Labels[labels[i]] != labels[i+1] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/testcontainers-go/metrics"
+)
+
+const exposition = `# HELP http_server_requests_seconds Duration of HTTP server request handling
+# TYPE http_server_requests_seconds summary
+http_server_requests_seconds_count{method="GET",status="200",uri="/api/services"} 3.0
+http_server_requests_seconds_sum{method="GET",status="200",uri="/api/services"} 0.25
+http_server_requests_seconds_count{method="POST",status="201",uri="/api/artifact/upload"} 2.0
+# TYPE mocks_invocations counter
+mocks_invocations_total{operation="GET /orders",status="200"} 2.0
+mocks_invocations_total{operation="GET /orders",status="404"} 1.0
+mocks_invocations_total{operation="DELETE /orders",status="204"} 0.0
+# TYPE jvm_threads_live_threads gauge
+jvm_threads_live_threads 42
+orphan_sample{label="with \"quotes\", and comma"} 1 1718000000000
+`
+
+func TestParse(t *testing.T) {
+	families, err := metrics.Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(families))
+
+	requests := families["http_server_requests_seconds"]
+	require.NotNil(t, requests)
+	require.Equal(t, "summary", requests.Type)
+	require.Equal(t, "Duration of HTTP server request handling", requests.Help)
+	require.Equal(t, 3, len(requests.Samples))
+	require.Equal(t, "http_server_requests_seconds_count", requests.Samples[0].Name)
+	require.Equal(t, "/api/services", requests.Samples[0].Labels["uri"])
+
+	threads := families["jvm_threads_live_threads"]
+	require.NotNil(t, threads)
+	require.Equal(t, "gauge", threads.Type)
+	require.Equal(t, 42.0, threads.Sum())
+
+	orphan := families["orphan_sample"]
+	require.NotNil(t, orphan)
+	require.Equal(t, "untyped", orphan.Type)
+	require.Equal(t, `with "quotes", and comma`, orphan.Samples[0].Labels["label"])
+}
+
+func TestFamilySum(t *testing.T) {
+	families, err := metrics.Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+
+	invocations := families["mocks_invocations"]
+	require.Equal(t, 3.0, invocations.Sum())
+	require.Equal(t, 3.0, invocations.Sum("operation", "GET /orders"))
+	require.Equal(t, 2.0, invocations.Sum("operation", "GET /orders", "status", "200"))
+	require.Equal(t, 0.0, invocations.Sum("operation", "PUT /orders"))
+}
+
+func TestFamilySumBy(t *testing.T) {
+	families, err := metrics.Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+
+	invocations := families["mocks_invocations"]
+	require.Equal(t, map[string]float64{"200": 2.0, "404": 1.0, "204": 0.0}, invocations.SumBy("status"))
+	require.Equal(t, map[string]float64{"200": 2.0, "404": 1.0}, invocations.SumBy("status", "operation", "GET /orders"))
+	require.Empty(t, invocations.SumBy("missing"))
+}
+
+func TestFamilyCountAndMean(t *testing.T) {
+	families, err := metrics.Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+
+	requests := families["http_server_requests_seconds"]
+	require.Equal(t, 5.0, requests.Count())
+	require.Equal(t, 3.0, requests.Count("method", "GET"))
+	require.InDelta(t, 0.25/3, requests.Mean("method", "GET"), 1e-9)
+	require.Equal(t, 0.0, requests.Mean("method", "PUT"))
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := metrics.Parse(strings.NewReader(`broken{label="value 1`))
+	require.Error(t, err)
+
+	_, err = metrics.Parse(strings.NewReader(`broken NaNa`))
+	require.Error(t, err)
+}
+
+func TestFilterAndWrite(t *testing.T) {
+	families, err := metrics.Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+
+	filtered := metrics.Filter(families, func(f *metrics.Family, s metrics.Sample) bool {
+		return strings.HasPrefix(s.Labels["operation"], "GET") || strings.HasPrefix(s.Labels["label"], "with")
+	})
+	require.Equal(t, 2, len(filtered))
+
+	var out strings.Builder
+	err = metrics.Write(&out, filtered)
+	require.NoError(t, err)
+	require.Equal(t, `# TYPE mocks_invocations counter
+mocks_invocations_total{operation="GET /orders",status="200"} 2
+mocks_invocations_total{operation="GET /orders",status="404"} 1
+# TYPE orphan_sample untyped
+orphan_sample{label="with \"quotes\", and comma"} 1
+`, out.String())
+
+	// Written metrics can be parsed back.
+	parsed, err := metrics.Parse(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	require.Equal(t, 3.0, parsed["mocks_invocations"].Sum())
+}
@@ -16,24 +16,49 @@
 package microcks
 
 import (
-	"bytes"
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"strconv"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
 	client "microcks.io/go-client"
+	apiclient "microcks.io/testcontainers-go/client"
+	"microcks.io/testcontainers-go/health"
+	"microcks.io/testcontainers-go/internal/cleanup"
+	"microcks.io/testcontainers-go/internal/logs"
+	"microcks.io/testcontainers-go/internal/platform"
+	"microcks.io/testcontainers-go/internal/proxy"
+	"microcks.io/testcontainers-go/internal/redact"
+	"microcks.io/testcontainers-go/internal/registry"
+	"microcks.io/testcontainers-go/internal/resources"
+	"microcks.io/testcontainers-go/internal/startup"
+	"microcks.io/testcontainers-go/metrics"
 )
 
+// statsDayLayout represents the layout of days used by Microcks to bucket invocation statistics.
+const statsDayLayout = "20060102"
+
+// mockRequestLogRegexp matches the log line written by Microcks when it serves a REST mock response. The Service
+// name may hold ", ", the version being after the last one.
+var mockRequestLogRegexp = regexp.MustCompile(`Servicing mock response for service \[([^\]]+), ([^,\]]+)\] on uri (\S+) with verb (\S+)`)
+
 const (
-	defaultImage = "quay.io/microcks/microcks-uber:latest"
+	// DefaultImage represents the default Microcks image.
+	DefaultImage = "quay.io/microcks/microcks-uber:latest"
 
 	// DefaultHttpPort represents the default Microcks HTTP port.
 	DefaultHttpPort = "8080/tcp"
@@ -43,17 +68,190 @@ const (
 
 	// DefaultNetworkAlias represents the default network alias of the the MicrocksContainer.
 	DefaultNetworkAlias = "microcks"
+
+	// HealthPath represents the path of the Microcks health endpoint.
+	HealthPath = "/api/health"
 )
 
+// InvocationStats represents the daily invocation statistics of a Service.
+type InvocationStats = apiclient.InvocationStats
+
+// InvocationProbe represents a probe counting the invocations of a Service from the moment it started.
+// It does not depend on ResetInvocationStats, so it can be used in subtests sharing the same container.
+type InvocationProbe struct {
+	container      *MicrocksContainer
+	serviceName    string
+	serviceVersion string
+	start          *InvocationStats
+}
+
+// MockRequest represents a request received by a REST mock endpoint.
+type MockRequest struct {
+	// ServiceName represents the name of the mocked Service.
+	ServiceName string
+
+	// ServiceVersion represents the version of the mocked Service.
+	ServiceVersion string
+
+	// Method represents the HTTP method of the request.
+	Method string
+
+	// URI represents the request URI, including the mock endpoint prefix.
+	URI string
+
+	// Headers represents the request headers, only available from a TrafficCapture.
+	Headers http.Header
+
+	// Body represents the request body, only available from a TrafficCapture.
+	Body []byte
+}
+
+// Option represents an option to pass to the Microcks container, applied once it is started and healthy.
+type Option func(*options) error
+
+// Customize implements testcontainers.ContainerCustomizer, so that options can be mixed with container ones.
+// Options are recorded by Run and Connect, not set on the container request: passing one to another module or to
+// testcontainers.GenericContainer is an error rather than a silently ignored setting.
+func (o Option) Customize(req *testcontainers.GenericContainerRequest) error {
+	settings, ok := recordingSettings(req)
+	if !ok {
+		return fmt.Errorf("error configuring container: microcks.Option must be passed to microcks.Run or microcks.Connect")
+	}
+	return o(settings)
+}
+
+// recordings holds the settings recording the options of the requests being customized by Run and Connect.
+var recordings sync.Map
+
+// recordedOptions holds the code pointers of the request options returned by recordOption.
+var recordedOptions sync.Map
+
+// record makes options customizing given request be recorded in settings, until the returned function is called.
+func (settings *options) record(req *testcontainers.GenericContainerRequest) func() {
+	recordings.Store(req, settings)
+	return func() { recordings.Delete(req) }
+}
+
+// recordingSettings returns the settings recording the options of given request, if any.
+func recordingSettings(req *testcontainers.GenericContainerRequest) (*options, bool) {
+	settings, ok := recordings.Load(req)
+	if !ok {
+		return nil, false
+	}
+	return settings.(*options), true
+}
+
+// recordOption returns a request option recording opt when passed to Run or Connect, so that it gets the same
+// initialization as other options. The fallback customizes the request otherwise, e.g. with lifecycle hooks when
+// passed to testcontainers.GenericContainer.
+func recordOption(opt Option, fallback testcontainers.CustomizeRequestOption) testcontainers.CustomizeRequestOption {
+	customize := func(req *testcontainers.GenericContainerRequest) error {
+		if settings, ok := recordingSettings(req); ok {
+			return opt(settings)
+		}
+		return fallback(req)
+	}
+	recordedOptions.Store(reflect.ValueOf(customize).Pointer(), struct{}{})
+	return customize
+}
+
+// isRecordedOption tells if given customizer is an option or a request option returned by recordOption.
+func isRecordedOption(opt testcontainers.ContainerCustomizer) bool {
+	switch opt := opt.(type) {
+	case Option:
+		return true
+	case testcontainers.CustomizeRequestOption:
+		_, ok := recordedOptions.Load(reflect.ValueOf(opt).Pointer())
+		return ok
+	}
+	return false
+}
+
+type options struct {
+	artifacts       []artifact
+	remoteArtifacts []remoteArtifact
+	snapshots       []string
+	secrets         []client.Secret
+	serviceAccount  *serviceAccount
+	credentials     staticCredentials
+	tls             *tlsSettings
+	grpcTLS         *tlsSettings
+
+	remoteGrpcEndpoint string
+	startupTimeout     time.Duration
+	logger             testcontainers.Logging
+	requestModifiers   []func(*testcontainers.GenericContainerRequest)
+
+	slowRequestThreshold time.Duration
+	progress             func(Progress)
+	apiTracing           apiTracing
+	retryPolicy          apiclient.RetryPolicy
+	rateLimiter          apiclient.RateLimiter
+	httpClient           *http.Client
+	roundTrippers        []func(http.RoundTripper) http.RoundTripper
+}
+
+type artifact struct {
+	path string
+	main bool
+}
+
+type remoteArtifact struct {
+	url        string
+	main       bool
+	secretName string
+}
+
 // MicrocksContainer represents the Microcks container type used in the module.
 type MicrocksContainer struct {
 	testcontainers.Container
+
+	serviceAccount *serviceAccount
+	credentials    staticCredentials
+	tls            *tlsSettings
+	grpcTLS        *tlsSettings
+
+	// remoteHttpEndpoint and remoteGrpcEndpoint are set when connected to a remote Microcks instance.
+	remoteHttpEndpoint string
+	remoteGrpcEndpoint string
+
+	logger               testcontainers.Logging
+	slowRequestThreshold time.Duration
+	progress             func(Progress)
+	apiTracing           apiTracing
+	retryPolicy          apiclient.RetryPolicy
+	rateLimiter          apiclient.RateLimiter
+	httpClient           *http.Client
+	roundTrippers        []func(http.RoundTripper) http.RoundTripper
+	serviceCache         *apiclient.ServiceCache
+
+	statsMutex    sync.Mutex
+	statsBaseline map[string]*InvocationStats
+	// statsSince represents when invocations started to be counted for Services having no baseline.
+	statsSince time.Time
+
+	eventsMutex  sync.Mutex
+	events       chan Event
+	eventsClosed bool
+
+	startupClock *startupClock
+
+	delaysMutex    sync.Mutex
+	originalDelays map[operationKey]int
 }
 
 // RunContainer creates an instance of the MicrocksContainer type.
+// Deprecated: use Run instead.
 func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*MicrocksContainer, error) {
+	return Run(ctx, DefaultImage, opts...)
+}
+
+// Run creates an instance of the MicrocksContainer type, using given image.
+// When the container started but could not be initialized, it is returned along with the error and should be
+// terminated by the caller.
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*MicrocksContainer, error) {
 	req := testcontainers.ContainerRequest{
-		Image:        defaultImage,
+		Image:        img,
 		ExposedPorts: []string{DefaultHttpPort, DefaultGrpcPort},
 		WaitingFor:   wait.ForLog("Started MicrocksApplication"),
 	}
@@ -62,16 +260,175 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 		Started:          true,
 	}
 
+	// Options are all validated, so that every misconfiguration is reported at once.
+	var errs []error
+	settings := options{}
+	stopRecording := settings.record(&genericContainerReq)
 	for _, opt := range opts {
-		opt.Customize(&genericContainerReq)
+		if err := opt.Customize(&genericContainerReq); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	stopRecording()
+	errs = append(errs, settings.validate()...)
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if err := registry.Apply(&genericContainerReq); err != nil {
+		return nil, err
+	}
+	if err := cleanup.Apply(&genericContainerReq); err != nil {
+		return nil, err
+	}
+	if settings.logger != nil {
+		genericContainerReq.Logger = settings.logger
+	}
+	if settings.startupTimeout > 0 {
+		genericContainerReq.WaitingFor = startup.Timeout(genericContainerReq.WaitingFor, settings.startupTimeout)
+	}
+	if settings.tls != nil {
+		settings.tls.customize(&genericContainerReq)
 	}
+	if settings.grpcTLS != nil {
+		settings.grpcTLS.customizeGrpc(&genericContainerReq)
+	}
+	for _, modifier := range settings.requestModifiers {
+		modifier(&genericContainerReq)
+	}
+	clock := newStartupClock()
+	genericContainerReq.LifecycleHooks = append(genericContainerReq.LifecycleHooks, clock.hooks())
 
 	container, err := testcontainers.GenericContainer(ctx, genericContainerReq)
+	if container == nil {
+		err = platform.Explain(ctx, genericContainerReq, container, err)
+		err = startup.Explain(ctx, "Microcks container", genericContainerReq, err)
+		return nil, fmt.Errorf("error starting Microcks container (%s): %w", redact.Request(genericContainerReq), err)
+	}
+	cleanup.TrackContainer(container)
+
+	// The container is returned along with any later error, so that callers can terminate it.
+	microcksContainer := &MicrocksContainer{
+		Container:            container,
+		serviceAccount:       settings.serviceAccount,
+		credentials:          settings.credentials,
+		tls:                  settings.tls,
+		grpcTLS:              settings.grpcTLS,
+		logger:               settings.logger,
+		slowRequestThreshold: settings.slowRequestThreshold,
+		progress:             settings.progress,
+		apiTracing:           settings.apiTracing,
+		retryPolicy:          settings.retryPolicy,
+		rateLimiter:          settings.rateLimiter,
+		httpClient:           settings.httpClient,
+		roundTrippers:        settings.roundTrippers,
+		serviceCache:         apiclient.NewServiceCache(),
+		startupClock:         clock,
+		statsSince:           time.Now(),
+	}
 	if err != nil {
-		return nil, err
+		err = platform.Explain(ctx, genericContainerReq, container, err)
+		err = startup.Explain(ctx, "Microcks container", genericContainerReq, err)
+		return microcksContainer, fmt.Errorf("error starting Microcks container (%s): %w", redact.Request(genericContainerReq), err)
+	}
+	microcksContainer.emit(Event{Type: EventContainerStarted})
+	if err := microcksContainer.initialize(ctx, &settings); err != nil {
+		return microcksContainer, err
 	}
 
-	return &MicrocksContainer{Container: container}, nil
+	return microcksContainer, nil
+}
+
+// validate returns the errors of options combinations that cannot work with a Microcks container.
+func (settings *options) validate() []error {
+	var errs []error
+	if settings.remoteGrpcEndpoint != "" {
+		errs = append(errs, fmt.Errorf("error configuring Microcks: a remote gRPC endpoint can only be used with Connect"))
+	}
+
+	mainArtifact := len(settings.snapshots) > 0
+	for _, a := range settings.artifacts {
+		mainArtifact = mainArtifact || a.main
+	}
+	for _, a := range settings.remoteArtifacts {
+		mainArtifact = mainArtifact || a.main
+	}
+	if !mainArtifact {
+		for _, a := range settings.artifacts {
+			errs = append(errs, fmt.Errorf("error configuring Microcks: secondary artifact %s needs a main artifact or a snapshot", a.path))
+		}
+		for _, a := range settings.remoteArtifacts {
+			errs = append(errs, fmt.Errorf("error configuring Microcks: secondary remote artifact %s needs a main artifact or a snapshot", a.url))
+		}
+	}
+	return errs
+}
+
+// initialize creates secrets and imports artifacts and snapshots once Microcks is available.
+func (container *MicrocksContainer) initialize(ctx context.Context, settings *options) error {
+	if container.startupClock == nil {
+		container.startupClock = newStartupClock()
+	}
+	container.startupClock.restart()
+
+	// Secrets come first, as artifacts import may depend on them.
+	for _, s := range settings.secrets {
+		container.logf("Creating secret %s", s.Name)
+		if _, err := container.CreateSecret(ctx, s); err != nil {
+			return err
+		}
+		container.lap("secret " + s.Name)
+	}
+	for _, a := range settings.artifacts {
+		container.logf("Importing artifact %s", a.path)
+		statusCode, err := container.importArtifact(ctx, a.path, a.main)
+		if err != nil {
+			return err
+		}
+		if statusCode != http.StatusCreated {
+			return apiclient.NewStatusError("import artifact "+a.path, statusCode, http.StatusCreated, ErrArtifactRejected)
+		}
+		container.lap("artifact " + a.path)
+	}
+	for _, a := range settings.remoteArtifacts {
+		container.logf("Importing remote artifact %s", a.url)
+		statusCode, err := container.ImportRemoteArtifact(ctx, a.url, a.main, a.secretName)
+		if err != nil {
+			return err
+		}
+		if statusCode != http.StatusCreated {
+			return apiclient.NewStatusError("import remote artifact "+a.url, statusCode, http.StatusCreated, ErrArtifactRejected)
+		}
+		container.lap("remote artifact " + a.url)
+	}
+	for _, snapshot := range settings.snapshots {
+		container.logf("Importing snapshot %s", snapshot)
+		statusCode, err := container.ImportSnapshot(ctx, snapshot)
+		if err != nil {
+			return err
+		}
+		if statusCode != http.StatusCreated {
+			return apiclient.NewStatusError("import snapshot "+snapshot, statusCode, http.StatusCreated, ErrArtifactRejected)
+		}
+		container.lap("snapshot " + snapshot)
+	}
+
+	return nil
+}
+
+// CleanupAll terminates every container and removes every network created by the module, including ensemble
+// members, that are not terminated yet. It guarantees a deterministic teardown in environments where Ryuk is
+// disabled, e.g. when called from TestMain once tests are done.
+func CleanupAll(ctx context.Context) error {
+	return cleanup.All(ctx)
+}
+
+// SetRegistryPrefix sets a registry prefix prepended to the images of every container created by the module,
+// including ensemble members, e.g. "registry.corp.example.com/mirror" pulls "quay.io/microcks/microcks-uber:latest"
+// as "registry.corp.example.com/mirror/quay.io/microcks/microcks-uber:latest". It allows air-gapped environments
+// mirroring images to use the module without changing every constructor call. It applies before image
+// substitutors provided with testcontainers.WithImageSubstitutors.
+func SetRegistryPrefix(registryPrefix string) {
+	registry.SetPrefix(registryPrefix)
 }
 
 // WithMainArtifact provides paths to artifacts that will be imported as main or main
@@ -91,20 +448,106 @@ func WithSecondaryArtifact(artifactFilePath string) testcontainers.CustomizeRequ
 // WithArtifact provides paths to artifacts that will be imported within the Microcks container.
 // Once it will be started and healthy.
 func WithArtifact(artifactFilePath string, main bool) testcontainers.CustomizeRequestOption {
-	return func(req *testcontainers.GenericContainerRequest) error {
-		hooks := testcontainers.ContainerLifecycleHooks{
-			PostReadies: []testcontainers.ContainerHook{
-				importArtifactHook(artifactFilePath, main),
-			},
+	return recordOption(
+		func(o *options) error {
+			o.artifacts = append(o.artifacts, artifact{path: artifactFilePath, main: main})
+			return nil
+		},
+		func(req *testcontainers.GenericContainerRequest) error {
+			hooks := testcontainers.ContainerLifecycleHooks{
+				PostReadies: []testcontainers.ContainerHook{
+					importArtifactHook(artifactFilePath, main),
+				},
+			}
+			req.LifecycleHooks = append(req.LifecycleHooks, hooks)
+			return nil
+		},
+	)
+}
+
+// WithMainRemoteArtifact provides URLs to artifacts that will be downloaded and imported as main ones
+// within the Microcks container.
+// Once it will be started and healthy.
+func WithMainRemoteArtifact(remoteArtifactURL string) Option {
+	return WithRemoteArtifact(remoteArtifactURL, true, "")
+}
+
+// WithSecondaryRemoteArtifact provides URLs to artifacts that will be downloaded and imported as secondary
+// ones within the Microcks container.
+// Once it will be started and healthy.
+func WithSecondaryRemoteArtifact(remoteArtifactURL string) Option {
+	return WithRemoteArtifact(remoteArtifactURL, false, "")
+}
+
+// WithRemoteArtifact provides URLs to artifacts that will be downloaded and imported within the Microcks
+// container, using the secret with given name (may be empty) for authentication or CA trust.
+// Once it will be started and healthy.
+func WithRemoteArtifact(remoteArtifactURL string, main bool, secretName string) Option {
+	return func(o *options) error {
+		o.remoteArtifacts = append(o.remoteArtifacts, remoteArtifact{url: remoteArtifactURL, main: main, secretName: secretName})
+		return nil
+	}
+}
+
+// WithSnapshots provides paths to Microcks repository snapshots that will be imported within the Microcks container.
+// Once it will be started and healthy.
+func WithSnapshots(snapshotFilePaths ...string) Option {
+	return func(o *options) error {
+		o.snapshots = append(o.snapshots, snapshotFilePaths...)
+		return nil
+	}
+}
+
+// WithMainRemoteArtifactFromGit provides a path to an artifact in a Git repository, at given ref (branch, tag or
+// commit), that will be downloaded and imported as main one within the Microcks container, using the secret with
+// given name (may be empty) for authentication. Repositories hosted on GitHub and GitLab are supported.
+// Once it will be started and healthy.
+func WithMainRemoteArtifactFromGit(repoURL, ref, path, secretName string) Option {
+	return func(o *options) error {
+		remoteArtifactURL, err := gitRawURL(repoURL, ref, path)
+		if err != nil {
+			return err
 		}
-		req.LifecycleHooks = append(req.LifecycleHooks, hooks)
 
+		return WithRemoteArtifact(remoteArtifactURL, true, secretName)(o)
+	}
+}
+
+// WithGitToken provides a token that will be stored within the Microcks container as a secret with given name,
+// so that artifacts can be downloaded from private Git repositories, using WithMainRemoteArtifactFromGit.
+func WithGitToken(secretName, token string) Option {
+	return func(o *options) error {
+		o.secrets = append(o.secrets, client.Secret{
+			Name:        secretName,
+			Description: "Git token",
+			Token:       &token,
+		})
+		return nil
+	}
+}
+
+// WithCACertificate provides a PEM encoded CA certificate file that will be stored within the Microcks
+// container as a secret with given name, so that remote artifacts can be downloaded from hosts signed
+// by this CA, using WithRemoteArtifact.
+func WithCACertificate(secretName, caCertFile string) Option {
+	return func(o *options) error {
+		caCert, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return fmt.Errorf("error reading CA certificate: %w", err)
+		}
+		caCertPem := string(caCert)
+
+		o.secrets = append(o.secrets, client.Secret{
+			Name:        secretName,
+			Description: "CA certificate from " + filepath.Base(caCertFile),
+			CaCertPem:   &caCertPem,
+		})
 		return nil
 	}
 }
 
 // WithNetwork allows to add a custom network.
-// Deprecated: Use network.WithNetwork from testcontainers instead.
+// Deprecated: Use WithDockerNetwork or network.WithNetwork from testcontainers instead.
 func WithNetwork(networkName string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		req.Networks = append(req.Networks, networkName)
@@ -112,8 +555,17 @@ func WithNetwork(networkName string) testcontainers.CustomizeRequestOption {
 	}
 }
 
+// WithDockerNetwork attaches the Microcks container to given network, as created by the testcontainers network
+// package, with given aliases or DefaultNetworkAlias when none is provided.
+func WithDockerNetwork(nw *testcontainers.DockerNetwork, aliases ...string) testcontainers.CustomizeRequestOption {
+	if len(aliases) == 0 {
+		aliases = []string{DefaultNetworkAlias}
+	}
+	return network.WithNetwork(aliases, nw)
+}
+
 // WithNetworkAlias allows to add a custom network alias for a specific network.
-// Deprecated: Use network.WithNetwork from testcontainers instead.
+// Deprecated: Use WithDockerNetwork or network.WithNetwork from testcontainers instead.
 func WithNetworkAlias(networkName, networkAlias string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		if req.NetworkAliases == nil {
@@ -125,6 +577,70 @@ func WithNetworkAlias(networkName, networkAlias string) testcontainers.Customize
 	}
 }
 
+// WithTmpfsStorage mounts the Microcks container temporary directory, where imported artifacts are written, on
+// tmpfs to avoid disk pressure on ephemeral CI nodes. The uber image already keeps its repository in memory, with
+// an embedded MongoDB-compatible store, so no data directory has to be moved.
+func WithTmpfsStorage() testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Tmpfs == nil {
+			req.Tmpfs = make(map[string]string)
+		}
+		req.Tmpfs["/tmp"] = "rw,exec"
+
+		return nil
+	}
+}
+
+// WithMemoryLimit limits the Microcks container memory to given bytes, without swap, so that it can be capped on
+// shared runners and is OOM-killed deterministically.
+func WithMemoryLimit(bytes int64) testcontainers.CustomizeRequestOption {
+	return resources.MemoryLimit(bytes)
+}
+
+// WithCPULimit limits the Microcks container to given number of CPUs, e.g. 1.5.
+func WithCPULimit(cpus float64) testcontainers.CustomizeRequestOption {
+	return resources.CPULimit(cpus)
+}
+
+// WithImagePlatform runs the Microcks image for given platform, e.g. "linux/amd64" on Apple Silicon when a pinned
+// image tag lacks an arm64 build. The Docker host must then be able to emulate it.
+func WithImagePlatform(imagePlatform string) testcontainers.CustomizeRequestOption {
+	return platform.Option(imagePlatform)
+}
+
+// WithLabels adds labels to the Microcks container, e.g. for cleanup policies or cost attribution.
+func WithLabels(labels map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		for key, value := range labels {
+			req.Labels[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithLifecycleHooks adds hooks to the Microcks container lifecycle, e.g. to run initialization once it is started
+// or capture logs before it is terminated.
+func WithLifecycleHooks(hooks ...testcontainers.ContainerLifecycleHooks) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, hooks...)
+
+		return nil
+	}
+}
+
+// WithRequestModifier modifies the Microcks container request with given function, e.g. to tweak settings not
+// covered by the options of the package. Modifiers are applied last, once the request is fully built.
+func WithRequestModifier(modifier func(*testcontainers.GenericContainerRequest)) Option {
+	return func(o *options) error {
+		o.requestModifiers = append(o.requestModifiers, modifier)
+		return nil
+	}
+}
+
 // WithEnv allows to add an environment variable.
 func WithEnv(key, value string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
@@ -137,6 +653,176 @@ func WithEnv(key, value string) testcontainers.CustomizeRequestOption {
 	}
 }
 
+// WithDebugLogging raises the Microcks log level to DEBUG, e.g. to diagnose dispatching rules not matching
+// incoming requests.
+func WithDebugLogging() testcontainers.CustomizeRequestOption {
+	return WithEnv("LOGGING_LEVEL_IO_GITHUB_MICROCKS", "DEBUG")
+}
+
+// WithOpenTelemetry enables the Microcks OpenTelemetry instrumentation, exporting traces to the OTLP collector at
+// given endpoint, e.g. "http://otel-collector:4317". Incoming W3C traceparent headers are honored, so that spans of
+// the application under test connect with the Microcks ones through mocked hops.
+func WithOpenTelemetry(otlpEndpoint string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if otlpEndpoint == "" {
+			return fmt.Errorf("error configuring OpenTelemetry: missing OTLP endpoint")
+		}
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		req.Env["OTEL_JAVA_GLOBAL_AUTOCONFIGURE_ENABLED"] = "true"
+		req.Env["OTEL_SERVICE_NAME"] = DefaultNetworkAlias
+		req.Env["OTEL_EXPORTER_OTLP_ENDPOINT"] = otlpEndpoint
+		req.Env["OTEL_PROPAGATORS"] = "tracecontext,baggage"
+
+		return nil
+	}
+}
+
+// WithProxy allows to route outgoing traffic through a corporate proxy.
+func WithProxy(httpProxy, httpsProxy, noProxy string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if req.Env == nil {
+			req.Env = make(map[string]string)
+		}
+		for key, value := range proxy.Env(httpProxy, httpsProxy, noProxy, true) {
+			req.Env[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the container lifecycle and module messages (imports, polling...), e.g.
+// testcontainers.TestLogger(t) so that they show up in the test output. testcontainers.Logger is used by default.
+func WithLogger(logger testcontainers.Logging) Option {
+	return func(o *options) error {
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithSlowRequestThreshold sets the duration above which mock requests going through a TrafficCapture are logged
+// as slow, e.g. to find a template script or a dispatcher dragging test time. See TrafficCapture.SlowExchanges.
+func WithSlowRequestThreshold(threshold time.Duration) Option {
+	return func(o *options) error {
+		if threshold <= 0 {
+			return fmt.Errorf("error setting slow request threshold: threshold must be positive, actual %s", threshold)
+		}
+		o.slowRequestThreshold = threshold
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy retrying failed calls to the Microcks API, e.g. an apiclient.Backoff, as shared
+// CI Docker hosts may drop connections while the container is warming up. Calls are not retried by default, and
+// only idempotent ones are unless the policy opts in, see apiclient.NonIdempotentRetryPolicy.
+func WithRetryPolicy(policy apiclient.RetryPolicy) Option {
+	return func(o *options) error {
+		if policy == nil {
+			return fmt.Errorf("error setting retry policy: policy is nil")
+		}
+		o.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithRateLimiter sets the limiter of the rate of calls to the Microcks API, e.g. apiclient.NewRateLimiter, so that
+// hundreds of goroutines calling Verify or statistics helpers do not overwhelm the container. It is shared by every
+// call made for the container. Calls are not limited by default.
+func WithRateLimiter(limiter apiclient.RateLimiter) Option {
+	return func(o *options) error {
+		if limiter == nil {
+			return fmt.Errorf("error setting rate limiter: limiter is nil")
+		}
+		o.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client the module uses to call Microcks, e.g. to set timeouts or go through a
+// corporate proxy. Its transport, when set, replaces the default one and has to trust the Microcks CA when TLS is
+// enabled. The client is copied: API tracing and retries do not alter it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		if httpClient == nil {
+			return fmt.Errorf("error setting HTTP client: client is nil")
+		}
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithRoundTripper wraps the transport the module uses to call Microcks, e.g. with otelhttp.NewTransport to
+// instrument calls. Wrappers are applied in order, the transport passed to the first one trusting the Microcks CA
+// when TLS is enabled.
+func WithRoundTripper(wrap func(next http.RoundTripper) http.RoundTripper) Option {
+	return func(o *options) error {
+		if wrap == nil {
+			return fmt.Errorf("error setting round tripper: wrapper is nil")
+		}
+		o.roundTrippers = append(o.roundTrippers, wrap)
+		return nil
+	}
+}
+
+// logf prints a module message using the container logger.
+func (container *MicrocksContainer) logf(format string, args ...any) {
+	logger := container.logger
+	if logger == nil {
+		logger = testcontainers.Logger
+	}
+	logger.Printf(format, args...)
+}
+
+// WithWaitStrategy replaces the default readiness detection of the Microcks container, waiting for the
+// "Started MicrocksApplication" log.
+func WithWaitStrategy(strategy wait.Strategy) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.WaitingFor = strategy
+
+		return nil
+	}
+}
+
+// WithStartupTimeout sets the time given to the Microcks container to be ready, 60 seconds by default. It applies
+// to the default wait strategy as well as to the one provided by WithWaitStrategy, independently of the context
+// given to Run. When exceeded, Run returns an error naming the container.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		if timeout <= 0 {
+			return fmt.Errorf("error configuring startup timeout: must be positive, actual %s", timeout)
+		}
+		o.startupTimeout = timeout
+		return nil
+	}
+}
+
+// WithReuse names the container and re-attaches to an already running container with the same name, so that
+// repeated local test runs don't pay the Microcks startup time. Artifacts and snapshots are imported again on
+// re-attachment, which updates existing services. To keep the container warm across `go test` runs, don't call
+// Terminate and disable Ryuk (TESTCONTAINERS_RYUK_DISABLED=true), as it removes containers once tests are done.
+func WithReuse(name string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.Name = name
+		req.Reuse = true
+
+		return nil
+	}
+}
+
+// WithHostAccess allows Microcks to reach given ports of the host, e.g. to contract-test a server started by the
+// test itself. Use HostEndpoint to get the URL of such a server from Microcks.
+func WithHostAccess(ports ...int) testcontainers.CustomizeRequestOption {
+	return WithHostAccessPorts(ports)
+}
+
+// HostEndpoint returns the HTTP endpoint of given host port, as reached from containers using WithHostAccess,
+// e.g. "http://host.testcontainers.internal:8080" to be used as TestEndpoint.
+func HostEndpoint(port int) string {
+	return fmt.Sprintf("http://%s:%d", testcontainers.HostInternal, port)
+}
+
 // WithHostAccessPorts allows to set the host access ports.
 func WithHostAccessPorts(hostAccessPorts []int) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
@@ -147,15 +833,38 @@ func WithHostAccessPorts(hostAccessPorts []int) testcontainers.CustomizeRequestO
 }
 
 // WithSecret allows to add a new secret.
+// Secret is created once the container is started and healthy, before artifacts are imported, so that it can
+// be used for remote artifacts download or secured test endpoints. Token, basic auth and CA certificate
+// secrets are supported.
 func WithSecret(s client.Secret) testcontainers.CustomizeRequestOption {
-	return func(req *testcontainers.GenericContainerRequest) error {
-		hooks := testcontainers.ContainerLifecycleHooks{
-			PostReadies: []testcontainers.ContainerHook{
-				createSecretHook(s),
-			},
-		}
-		req.LifecycleHooks = append(req.LifecycleHooks, hooks)
+	return recordOption(
+		func(o *options) error {
+			o.secrets = append(o.secrets, s)
+			return nil
+		},
+		func(req *testcontainers.GenericContainerRequest) error {
+			hooks := testcontainers.ContainerLifecycleHooks{
+				PostReadies: []testcontainers.ContainerHook{
+					createSecretHook(s),
+				},
+			}
+			// Secrets hooks come first, as artifacts import may depend on them.
+			req.LifecycleHooks = append([]testcontainers.ContainerLifecycleHooks{hooks}, req.LifecycleHooks...)
+			return nil
+		},
+	)
+}
 
+// WithServiceAccount allows to authenticate API calls when Microcks runs with Keycloak enabled.
+// An access token is obtained from the Keycloak endpoint reachable from the host, using the client
+// credentials of the service account, and refreshed automatically once expired.
+func WithServiceAccount(keycloakEndpoint, clientID, clientSecret string) Option {
+	return func(o *options) error {
+		o.serviceAccount = &serviceAccount{
+			keycloakEndpoint: keycloakEndpoint,
+			clientID:         clientID,
+			clientSecret:     clientSecret,
+		}
 		return nil
 	}
 }
@@ -163,6 +872,10 @@ func WithSecret(s client.Secret) testcontainers.CustomizeRequestOption {
 // HttpEndpoint allows retrieving the Http endpoint where Microcks can be accessed.
 // (you'd have to append '/api' to access APIs)
 func (container *MicrocksContainer) HttpEndpoint(ctx context.Context) (string, error) {
+	if container.IsRemote() {
+		return container.remoteHttpEndpoint, nil
+	}
+
 	ip, err := container.Host(ctx)
 	if err != nil {
 		return "", err
@@ -173,7 +886,7 @@ func (container *MicrocksContainer) HttpEndpoint(ctx context.Context) (string, e
 		return "", err
 	}
 
-	return fmt.Sprintf("http://%s:%s", ip, port.Port()), nil
+	return container.scheme() + "://" + net.JoinHostPort(ip, port.Port()), nil
 }
 
 // SoapMockEndpoint get the exposed mock endpoint for a SOAP Service.
@@ -208,6 +921,13 @@ func (container *MicrocksContainer) GraphQLMockEndpoint(ctx context.Context, ser
 
 // GrpcMockEndpoint get the exposed mock endpoint for a GRPC Service.
 func (container *MicrocksContainer) GrpcMockEndpoint(ctx context.Context) (string, error) {
+	if container.IsRemote() {
+		if container.remoteGrpcEndpoint == "" {
+			return "", fmt.Errorf("error retrieving gRPC endpoint: no gRPC endpoint provided for the remote Microcks instance")
+		}
+		return container.remoteGrpcEndpoint, nil
+	}
+
 	ip, err := container.Host(ctx)
 	if err != nil {
 		return "", err
@@ -218,7 +938,7 @@ func (container *MicrocksContainer) GrpcMockEndpoint(ctx context.Context) (strin
 		return "", err
 	}
 
-	return fmt.Sprintf("grpc://%s:%s", ip, port.Port()), nil
+	return "grpc://" + net.JoinHostPort(ip, port.Port()), nil
 }
 
 // ImportAsMainArtifact imports an artifact as a primary or main one within the Microcks container.
@@ -231,143 +951,560 @@ func (container *MicrocksContainer) ImportAsSecondaryArtifact(ctx context.Contex
 	return container.importArtifact(ctx, artifactFilePath, false)
 }
 
-// TestEndpoint launches a conformance test on an endpoint.
-func (container *MicrocksContainer) TestEndpoint(ctx context.Context, testRequest *client.TestRequest) (*client.TestResult, error) {
-	// Retrieve API endpoint.
-	httpEndpoint, err := container.HttpEndpoint(ctx)
+// ImportSnapshot imports a Microcks repository snapshot, as exported from another Microcks instance, within
+// the Microcks container.
+func (container *MicrocksContainer) ImportSnapshot(ctx context.Context, snapshotFilePath string) (int, error) {
+	file, err := os.Open(snapshotFilePath)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("error opening snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	return container.importSnapshot(ctx, filepath.Base(snapshotFilePath), file, fileSize(file))
+}
+
+// ImportRemoteArtifact downloads and imports an artifact within the Microcks container, using the secret
+// with given name (may be empty) for authentication or CA trust.
+func (container *MicrocksContainer) ImportRemoteArtifact(ctx context.Context, remoteArtifactURL string, mainArtifact bool, secretName string) (int, error) {
+	c, err := container.APIClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving Microcks API endpoint: %w", err)
+		return http.StatusInternalServerError, err
+	}
+
+	tracker := container.trackProgress(ProgressArtifactImport, remoteArtifactURL)
+	tracker.report()
+	statusCode, err := createdStatusCode(c.DownloadArtifact(ctx, remoteArtifactURL, mainArtifact, secretName))
+	if err != nil {
+		return statusCode, err
+	}
+	tracker.done()
+
+	if statusCode == http.StatusCreated {
+		container.emit(Event{Type: EventArtifactImported, Subject: remoteArtifactURL})
 	}
+	return statusCode, nil
+}
 
-	// Create Microcks client.
-	c, err := client.NewClientWithResponses(httpEndpoint + "/api")
+// TestEndpoint launches a conformance test on an endpoint. When the test is still in progress after its timeout, its
+// result is returned with an error matching ErrTestTimeout.
+func (container *MicrocksContainer) TestEndpoint(ctx context.Context, testRequest *client.TestRequest) (*client.TestResult, error) {
+	c, err := container.APIClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error creating Microcks client: %w", err)
+		return nil, err
 	}
 
-	testResult, err := c.CreateTestWithResponse(ctx, *testRequest)
+	testResult, err := c.CreateTest(ctx, *testRequest)
+	var statusErr *apiclient.StatusError
+	if errors.As(err, &statusErr) {
+		return nil, fmt.Errorf("couldn't launch on new test on Microcks. Please check Microcks container logs: %w", err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error creating test with response: %w", err)
 	}
 
-	if testResult.HTTPResponse.StatusCode == 201 {
-		// Retrieve Id and start polling for final result.
-		var testResultId string = testResult.JSON201.Id
+	// Retrieve Id and start polling for final result.
+	var testResultId string = testResult.Id
+	container.emit(Event{Type: EventTestLaunched, Subject: testRequest.ServiceId})
+	tracker := container.trackProgress(ProgressTestPolling, testRequest.ServiceId)
 
-		// Wait an initial delay to avoid inefficient poll.
-		time.Sleep(100 * time.Millisecond)
+	// Wait an initial delay to avoid inefficient poll.
+	if err := sleep(ctx, 100*time.Millisecond); err != nil {
+		return nil, err
+	}
 
-		// Compute future time that is the end of waiting time frame.
-		future := nowInMilliseconds() + int64(testRequest.Timeout)
-		for nowInMilliseconds() < future {
-			testResultResponse, err := c.GetTestResultWithResponse(ctx, testResultId)
-			if err != nil {
-				return nil, fmt.Errorf("error getting test result with response: %w", err)
-			}
+	// Compute future time that is the end of waiting time frame.
+	future := nowInMilliseconds() + int64(testRequest.Timeout)
+	for nowInMilliseconds() < future {
+		testResult, err := c.GetTestResult(ctx, testResultId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting test result with response: %w", err)
+		}
 
-			// If still in progress, then wait again.
-			if testResultResponse.JSON200.InProgress {
-				time.Sleep(200 * time.Millisecond)
-			} else {
-				break
-			}
+		// If still in progress, then wait again.
+		if !testResult.InProgress {
+			break
 		}
+		tracker.report()
+		if err := sleep(ctx, 200*time.Millisecond); err != nil {
+			return nil, err
+		}
+	}
 
-		// Return the final result.
-		response, err := c.GetTestResultWithResponse(ctx, testResultId)
-		return response.JSON200, err
+	// Return the final result.
+	testResult, err = c.GetTestResult(ctx, testResultId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting test result with response: %w", err)
+	}
+	tracker.done()
+	container.emit(Event{Type: EventTestFinished, Subject: testRequest.ServiceId, TestResult: testResult})
+	if testResult.InProgress {
+		return testResult, fmt.Errorf("test %s still in progress after %d ms: %w", testResultId, testRequest.Timeout, ErrTestTimeout)
 	}
-	return nil, fmt.Errorf("couldn't launch on new test on Microcks. Please check Microcks container logs")
+	return testResult, nil
 }
 
-func importArtifactHook(artifactFilePath string, mainArtifact bool) testcontainers.ContainerHook {
+// Verify checks that given Service has been invoked at least one time, for the current invocations statistics.
+func (container *MicrocksContainer) Verify(ctx context.Context, serviceName string, serviceVersion string) (bool, error) {
+	count, err := container.ServiceInvocationsCount(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// ServiceInvocationsCount gets the invocations count of given Service, for the current invocations statistics.
+// Invocations are counted from the last call to ResetInvocationStats, if any, or from the day the container was
+// started or connected, even across a day boundary.
+func (container *MicrocksContainer) ServiceInvocationsCount(ctx context.Context, serviceName string, serviceVersion string) (int, error) {
+	if err := waitForStats(ctx); err != nil {
+		return 0, err
+	}
+
+	container.statsMutex.Lock()
+	baseline, ok := container.statsBaseline[serviceName+":"+serviceVersion]
+	if !ok {
+		// Counting from the whole first day, rather than from the current one, survives UTC midnight.
+		if container.statsSince.IsZero() {
+			container.statsSince = time.Now()
+		}
+		baseline = &InvocationStats{
+			ServiceName:    serviceName,
+			ServiceVersion: serviceVersion,
+			Day:            container.statsSince.UTC().Format(statsDayLayout),
+		}
+	}
+	container.statsMutex.Unlock()
+
+	return container.countInvocationsSince(ctx, serviceName, serviceVersion, baseline)
+}
+
+// WaitForInvocations waits for given Service to be invoked at least n times, polling the current invocations
+// statistics until timeout is reached.
+func (container *MicrocksContainer) WaitForInvocations(ctx context.Context, serviceName string, serviceVersion string, n int, timeout time.Duration) error {
+	container.logf("Waiting for %d invocations of %s:%s", n, serviceName, serviceVersion)
+	deadline := time.Now().Add(timeout)
+	for {
+		count, err := container.ServiceInvocationsCount(ctx, serviceName, serviceVersion)
+		if err != nil {
+			return err
+		}
+		if count >= n {
+			container.emit(Event{Type: EventInvocationsReached, Subject: serviceName + ":" + serviceVersion})
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service %s:%s has been invoked %d times, expected at least %d within %s", serviceName, serviceVersion, count, n, timeout)
+		}
+
+		// Wait again before polling.
+		if err := sleep(ctx, 200*time.Millisecond); err != nil {
+			return err
+		}
+	}
+}
+
+// ServiceInvocationStats gets the detailed invocation statistics of given Service, for the current day.
+// Microcks aggregates invocations at the Service level, so the breakdown is done by hour and by minute.
+// Statistics are relative to the last call to ResetInvocationStats, if any.
+func (container *MicrocksContainer) ServiceInvocationStats(ctx context.Context, serviceName string, serviceVersion string) (*InvocationStats, error) {
+	if err := waitForStats(ctx); err != nil {
+		return nil, err
+	}
+
+	stats, err := container.fetchInvocationStats(ctx, serviceName, serviceVersion, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	container.statsMutex.Lock()
+	defer container.statsMutex.Unlock()
+	if baseline, ok := container.statsBaseline[serviceName+":"+serviceVersion]; ok {
+		subtractStats(stats, baseline)
+	}
+
+	return stats, nil
+}
+
+// StartProbe starts an InvocationProbe on given Service, recording its current invocations count.
+func (container *MicrocksContainer) StartProbe(ctx context.Context, serviceName string, serviceVersion string) (*InvocationProbe, error) {
+	if err := waitForStats(ctx); err != nil {
+		return nil, err
+	}
+
+	stats, err := container.fetchInvocationStats(ctx, serviceName, serviceVersion, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvocationProbe{
+		container:      container,
+		serviceName:    serviceName,
+		serviceVersion: serviceVersion,
+		start:          stats,
+	}, nil
+}
+
+// Delta gets the number of invocations of the probed Service since the probe started.
+func (probe *InvocationProbe) Delta(ctx context.Context) (int, error) {
+	if err := waitForStats(ctx); err != nil {
+		return 0, err
+	}
+
+	return probe.container.countInvocationsSince(ctx, probe.serviceName, probe.serviceVersion, probe.start)
+}
+
+// ReceivedRequests gets the requests received by the REST mock endpoints of given Service, in order of arrival.
+// Microcks does not store received requests, so they are extracted from the INFO logs of the container: only the
+// method and URI are available. Use TrafficCapture.ReceivedRequests to get the headers and payloads too.
+func (container *MicrocksContainer) ReceivedRequests(ctx context.Context, serviceName string, serviceVersion string) ([]MockRequest, error) {
+	if container.IsRemote() {
+		return nil, fmt.Errorf("error retrieving received requests: Microcks container logs are not available on a remote instance")
+	}
+
+	logs, err := container.Logs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving Microcks container logs: %w", err)
+	}
+	defer logs.Close()
+
+	return parseMockRequests(logs, serviceName, serviceVersion)
+}
+
+// parseMockRequests parses the requests received by the REST mock endpoints of given Service from Microcks logs.
+func parseMockRequests(logs io.Reader, serviceName string, serviceVersion string) ([]MockRequest, error) {
+	var requests []MockRequest
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		matches := mockRequestLogRegexp.FindStringSubmatch(scanner.Text())
+		if matches == nil || matches[1] != serviceName || matches[2] != serviceVersion {
+			continue
+		}
+
+		uri := matches[3]
+		if unescaped, err := url.PathUnescape(uri); err == nil {
+			uri = unescaped
+		}
+		requests = append(requests, MockRequest{
+			ServiceName:    matches[1],
+			ServiceVersion: matches[2],
+			Method:         matches[4],
+			URI:            uri,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Microcks container logs: %w", err)
+	}
+
+	return requests, nil
+}
+
+// FollowLogs streams the Microcks container logs to w, from its start and until ctx is done, e.g. to watch a flaky
+// test live. It blocks, so it is usually run in its own goroutine.
+func (container *MicrocksContainer) FollowLogs(ctx context.Context, w io.Writer) error {
+	if container.IsRemote() {
+		return fmt.Errorf("error following logs: Microcks container logs are not available on a remote instance")
+	}
+	return logs.Follow(ctx, container.GetContainerID(), w)
+}
+
+// ServiceInvocationStatsOnDay gets the detailed invocation statistics of given Service, for the given day.
+// Microcks buckets statistics by UTC day, the time of day is ignored. Statistics are not relative to
+// the last call to ResetInvocationStats.
+func (container *MicrocksContainer) ServiceInvocationStatsOnDay(ctx context.Context, serviceName string, serviceVersion string, day time.Time) (*InvocationStats, error) {
+	if err := waitForStats(ctx); err != nil {
+		return nil, err
+	}
+
+	return container.fetchInvocationStats(ctx, serviceName, serviceVersion, day)
+}
+
+// ResetInvocationStats resets the invocation statistics of all Services, so that following
+// Verify, ServiceInvocationsCount and ServiceInvocationStats calls start from zero.
+// Microcks has no API to clear its statistics, so current values are recorded as a baseline.
+func (container *MicrocksContainer) ResetInvocationStats(ctx context.Context) error {
+	if err := waitForStats(ctx); err != nil {
+		return err
+	}
+
+	services, err := container.listServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	baseline := make(map[string]*InvocationStats, len(services))
+	for _, service := range services {
+		stats, err := container.fetchInvocationStats(ctx, service.Name, service.Version, now)
+		if err != nil {
+			return err
+		}
+		baseline[service.Name+":"+service.Version] = stats
+	}
+
+	container.statsMutex.Lock()
+	defer container.statsMutex.Unlock()
+	container.statsBaseline = baseline
+
+	return nil
+}
+
+// CreateSecret creates a new secret within the running Microcks container and returns it with its identifier.
+func (container *MicrocksContainer) CreateSecret(ctx context.Context, s client.Secret) (*client.Secret, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := c.CreateSecret(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create secret %s: %w", s.Name, err)
+	}
+	return created, nil
+}
+
+func createSecretHook(s client.Secret) testcontainers.ContainerHook {
 	return func(ctx context.Context, container testcontainers.Container) error {
 		microcksContainer := &MicrocksContainer{Container: container}
-		_, err := microcksContainer.importArtifact(ctx, artifactFilePath, mainArtifact)
+		_, err := microcksContainer.CreateSecret(ctx, s)
 		return err
 	}
 }
 
-func (container *MicrocksContainer) importArtifact(ctx context.Context, artifactFilePath string, mainArtifact bool) (int, error) {
-	// Retrieve API endpoint.
-	httpEndpoint, err := container.HttpEndpoint(ctx)
+// Secrets lists the secrets of the running Microcks container.
+func (container *MicrocksContainer) Secrets(ctx context.Context) ([]client.Secret, error) {
+	c, err := container.APIClient(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error retrieving Microcks API endpoint: %w", err)
+		return nil, err
 	}
 
-	// Create Microcks client.
-	c, err := client.NewClientWithResponses(httpEndpoint + "/api")
+	return c.ListSecrets(ctx)
+}
+
+// UpdateSecret updates the secret having given identifier within the running Microcks container,
+// allowing to rotate credentials without recreating the container.
+func (container *MicrocksContainer) UpdateSecret(ctx context.Context, id string, s client.Secret) error {
+	c, err := container.APIClient(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error creating Microcks client: %w", err)
+		return err
 	}
 
-	// Ensure file exists on fs.
-	file, err := os.Open(artifactFilePath)
+	return c.UpdateSecret(ctx, id, s)
+}
+
+// DeleteSecret deletes the secret having given identifier within the running Microcks container.
+func (container *MicrocksContainer) DeleteSecret(ctx context.Context, id string) error {
+	c, err := container.APIClient(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error opening artifact file: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	// Create a multipart request body, reading the file.
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", filepath.Base(artifactFilePath))
+	return c.DeleteSecret(ctx, id)
+}
+
+// ExportMetrics writes the Microcks Prometheus metrics relevant to mocks and tests to w, in the Prometheus
+// text format, so that they can be pushed to a Pushgateway.
+func (container *MicrocksContainer) ExportMetrics(ctx context.Context, w io.Writer) error {
+	families, err := container.Metrics(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error creating multipart form: %w", err)
+		return err
 	}
 
-	_, err = io.Copy(part, file)
+	// Keep Microcks own metrics and HTTP metrics of mock and test endpoints.
+	relevant := metrics.Filter(families, func(f *metrics.Family, s metrics.Sample) bool {
+		if strings.HasPrefix(f.Name, "microcks_") {
+			return true
+		}
+		for _, prefix := range []string{"/rest/", "/soap/", "/graphql/", "/dynarest/", "/api/tests"} {
+			if strings.HasPrefix(s.Labels["uri"], prefix) {
+				return true
+			}
+		}
+		return false
+	})
+
+	return metrics.Write(w, relevant)
+}
+
+// Health gets the Microcks health status, reported by its "/api/health" endpoint.
+func (container *MicrocksContainer) Health(ctx context.Context) (*health.Status, error) {
+	endpoint, err := container.HttpEndpoint(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error copying file to multipart form: %w", err)
+		return nil, err
 	}
 
-	// Add the mainArtifact flag to request.
-	_ = writer.WriteField("mainArtifact", strconv.FormatBool(mainArtifact))
-	err = writer.Close()
+	return health.Get(ctx, container.apiHTTPClient(), endpoint+HealthPath)
+}
+
+// IsReady tells if Microcks is up, e.g. for a sanity check in the middle of a test.
+func (container *MicrocksContainer) IsReady(ctx context.Context) bool {
+	status, err := container.Health(ctx)
+	return err == nil && status.IsUp()
+}
+
+// Metrics scrapes the Microcks Prometheus metrics and returns them by family name, e.g. to assert on the
+// "http_server_requests_seconds" latency and throughput of mock endpoints.
+func (container *MicrocksContainer) Metrics(ctx context.Context) (map[string]*metrics.Family, error) {
+	endpoint, err := container.MetricsEndpoint(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error closing multipart form: %w", err)
+		return nil, err
 	}
 
-	response, err := c.UploadArtifactWithBody(ctx, nil, writer.FormDataContentType(), body)
+	return metrics.ScrapeWithClient(ctx, container.apiHTTPClient(), endpoint)
+}
+
+// MetricsEndpoint returns the endpoint exposing the Microcks metrics in the Prometheus text format.
+func (container *MicrocksContainer) MetricsEndpoint(ctx context.Context) (string, error) {
+	endpoint, err := container.HttpEndpoint(ctx)
 	if err != nil {
-		return 0, err
+		return "", err
 	}
-	return response.StatusCode, err
+
+	return endpoint + "/actuator/prometheus", nil
 }
 
-func createSecretHook(s client.Secret) testcontainers.ContainerHook {
+func importArtifactHook(artifactFilePath string, mainArtifact bool) testcontainers.ContainerHook {
 	return func(ctx context.Context, container testcontainers.Container) error {
 		microcksContainer := &MicrocksContainer{Container: container}
-		statusCode, err := microcksContainer.createSecret(ctx, s)
+		statusCode, err := microcksContainer.importArtifact(ctx, artifactFilePath, mainArtifact)
+		if err != nil {
+			return err
+		}
 		if statusCode != http.StatusCreated {
-			return fmt.Errorf("unable to create secret, bad status code, actual %d, expected %d, ", statusCode, http.StatusCreated)
+			return apiclient.NewStatusError("import artifact "+artifactFilePath, statusCode, http.StatusCreated, ErrArtifactRejected)
 		}
-		return err
+		return nil
 	}
 }
 
-func (container *MicrocksContainer) createSecret(ctx context.Context, s client.Secret) (int, error) {
-	// Retrieve API endpoint.
-	httpEndpoint, err := container.HttpEndpoint(ctx)
+func (container *MicrocksContainer) importArtifact(ctx context.Context, artifactFilePath string, mainArtifact bool) (int, error) {
+	c, err := container.APIClient(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error retrieving Microcks API endpoint: %w", err)
+		return http.StatusInternalServerError, err
 	}
 
-	// Create Microcks client.
-	c, err := client.NewClientWithResponses(httpEndpoint + "/api")
+	// Ensure file exists on fs.
+	file, err := os.Open(artifactFilePath)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("error creating Microcks client: %w", err)
+		return http.StatusInternalServerError, fmt.Errorf("error opening artifact file: %w", err)
 	}
+	defer file.Close()
 
-	// Create secret.
-	var none client.RequestEditorFn = func(ctx context.Context, req *http.Request) error {
-		return nil
+	tracker := container.trackProgress(ProgressArtifactImport, artifactFilePath)
+	statusCode, err := createdStatusCode(c.UploadArtifact(ctx, filepath.Base(artifactFilePath), tracker.upload(file, fileSize(file)), mainArtifact))
+	if err != nil {
+		return statusCode, err
+	}
+	tracker.done()
+
+	if statusCode == http.StatusCreated {
+		container.emit(Event{Type: EventArtifactImported, Subject: artifactFilePath})
 	}
-	response, err := c.CreateSecret(ctx, s, none)
+	return statusCode, nil
+}
+
+// countInvocationsSince counts the invocations of a Service since the start statistics were fetched.
+// When days rolled over since then, the remaining invocations of the previous days are added.
+func (container *MicrocksContainer) countInvocationsSince(ctx context.Context, serviceName string, serviceVersion string, start *InvocationStats) (int, error) {
+	now := time.Now()
+	current, err := container.fetchInvocationStats(ctx, serviceName, serviceVersion, now)
 	if err != nil {
 		return 0, err
 	}
-	return response.StatusCode, err
+	if start == nil {
+		return current.DailyCount, nil
+	}
+	if start.Day == current.Day {
+		return current.DailyCount - start.DailyCount, nil
+	}
+
+	startDay, err := time.Parse(statsDayLayout, start.Day)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing invocation statistics day: %w", err)
+	}
+
+	count := current.DailyCount - start.DailyCount
+	for day := startDay; day.Format(statsDayLayout) < current.Day; day = day.AddDate(0, 0, 1) {
+		stats, err := container.fetchInvocationStats(ctx, serviceName, serviceVersion, day)
+		if err != nil {
+			return 0, err
+		}
+		count += stats.DailyCount
+	}
+
+	return count, nil
+}
+
+func (container *MicrocksContainer) fetchInvocationStats(ctx context.Context, serviceName string, serviceVersion string, date time.Time) (*InvocationStats, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.InvocationStats(ctx, serviceName, serviceVersion, date)
+}
+
+func (container *MicrocksContainer) listServices(ctx context.Context) ([]apiclient.Service, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ListServices(ctx)
+}
+
+func subtractStats(stats *InvocationStats, baseline *InvocationStats) {
+	if stats.Day != baseline.Day {
+		return
+	}
+
+	if stats.HourlyCount == nil {
+		stats.HourlyCount = make(map[string]int)
+	}
+	if stats.MinuteCount == nil {
+		stats.MinuteCount = make(map[string]int)
+	}
+
+	stats.DailyCount -= baseline.DailyCount
+	for hour, count := range baseline.HourlyCount {
+		stats.HourlyCount[hour] -= count
+	}
+	for minute, count := range baseline.MinuteCount {
+		stats.MinuteCount[minute] -= count
+	}
+}
+
+// gitRawURL computes the URL of the raw content of a file in a Git repository.
+func gitRawURL(repoURL, ref, path string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(repoURL, ".git"))
+	if err != nil {
+		return "", fmt.Errorf("error parsing Git repository URL: %w", err)
+	}
+	repoPath := strings.Trim(u.Path, "/")
+	path = strings.TrimPrefix(path, "/")
+
+	if u.Host == "github.com" {
+		return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", repoPath, ref, path), nil
+	}
+	return fmt.Sprintf("%s://%s/%s/-/raw/%s/%s", u.Scheme, u.Host, repoPath, ref, path), nil
+}
+
+// waitForStats waits a bit for invocation statistics to be up to date, as Microcks updates them asynchronously.
+func waitForStats(ctx context.Context) error {
+	return sleep(ctx, 100*time.Millisecond)
+}
+
+// sleep waits for given duration, returning early with the context error when ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func nowInMilliseconds() int64 {
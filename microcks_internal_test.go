@@ -0,0 +1,1239 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	client "microcks.io/go-client"
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+func TestUnitGitRawURL(t *testing.T) {
+	rawURL, err := gitRawURL("https://github.com/microcks/microcks.git", "1.9.x", "/samples/APIPastry-openapi.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "https://raw.githubusercontent.com/microcks/microcks/1.9.x/samples/APIPastry-openapi.yaml", rawURL)
+
+	rawURL, err = gitRawURL("https://gitlab.internal.corp/apis/contracts", "main", "orders/openapi.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "https://gitlab.internal.corp/apis/contracts/-/raw/main/orders/openapi.yaml", rawURL)
+}
+
+func TestUnitAuthHandler(t *testing.T) {
+	handler := authHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), []AuthRequirement{
+		{Method: http.MethodGet, PathPrefix: "/rest/API Pastries/0.0.1/pastries", BearerToken: "secret"},
+		{PathPrefix: "/rest/API Orders", APIKeyHeader: "X-Api-Key", APIKey: "key"},
+	})
+
+	call := func(method, path string, headers map[string]string) int {
+		req := httptest.NewRequest(method, "http://localhost"+path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, call(http.MethodGet, "/rest/API%20Pastries/0.0.1/pastries", nil))
+	require.Equal(t, http.StatusForbidden, call(http.MethodGet, "/rest/API%20Pastries/0.0.1/pastries", map[string]string{"Authorization": "Bearer wrong"}))
+	require.Equal(t, http.StatusOK, call(http.MethodGet, "/rest/API%20Pastries/0.0.1/pastries", map[string]string{"Authorization": "Bearer secret"}))
+	require.Equal(t, http.StatusOK, call(http.MethodPost, "/rest/API%20Pastries/0.0.1/pastries", nil))
+	require.Equal(t, http.StatusUnauthorized, call(http.MethodPost, "/rest/API%20Orders/1.0/orders", nil))
+	require.Equal(t, http.StatusForbidden, call(http.MethodPost, "/rest/API%20Orders/1.0/orders", map[string]string{"X-Api-Key": "nope"}))
+	require.Equal(t, http.StatusOK, call(http.MethodPost, "/rest/API%20Orders/1.0/orders", map[string]string{"X-Api-Key": "key"}))
+	require.Equal(t, http.StatusOK, call(http.MethodGet, "/rest/Other/1.0/things", nil))
+}
+
+func TestUnitConnect(t *testing.T) {
+	_, err := Connect(context.Background(), "microcks.example.com:8080")
+	require.Error(t, err)
+
+	container, err := Connect(context.Background(), "https://microcks.example.com/", WithRemoteGrpcEndpoint("grpc://microcks-grpc.example.com:443"))
+	require.NoError(t, err)
+	require.True(t, container.IsRemote())
+
+	endpoint, err := container.RestMockEndpoint(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, "https://microcks.example.com/rest/API Pastries/0.0.1", endpoint)
+
+	endpoint, err = container.GrpcMockEndpoint(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "grpc://microcks-grpc.example.com:443", endpoint)
+
+	_, err = container.ReceivedRequests(context.Background(), "API Pastries", "0.0.1")
+	require.Error(t, err)
+
+	// Container features are unavailable rather than panicking.
+	require.Empty(t, container.GetContainerID())
+	require.False(t, container.IsRunning())
+	_, err = container.Host(context.Background())
+	require.Error(t, err)
+	_, err = container.MappedPort(context.Background(), DefaultHttpPort)
+	require.Error(t, err)
+	_, err = container.State(context.Background())
+	require.Error(t, err)
+	require.NoError(t, container.Terminate(context.Background()))
+}
+
+func TestUnitWithStartupTimeout(t *testing.T) {
+	_, err := Run(context.Background(), DefaultImage, WithStartupTimeout(0))
+	require.Error(t, err)
+}
+
+func TestUnitResourceLimits(t *testing.T) {
+	_, err := Run(context.Background(), DefaultImage, WithMemoryLimit(0))
+	require.Error(t, err)
+
+	_, err = Run(context.Background(), DefaultImage, WithCPULimit(0))
+	require.Error(t, err)
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestUnitLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	container, err := Connect(context.Background(), "http://localhost:8080", WithLogger(logger))
+	require.NoError(t, err)
+
+	container.logf("Importing artifact %s", "apipastries-openapi.yaml")
+	require.Equal(t, []string{"Importing artifact apipastries-openapi.yaml"}, logger.messages)
+}
+
+func TestUnitHostEndpoint(t *testing.T) {
+	require.Equal(t, "http://host.testcontainers.internal:8080", HostEndpoint(8080))
+}
+
+func TestUnitOptionsValidation(t *testing.T) {
+	_, err := Run(context.Background(), DefaultImage,
+		WithSecondaryArtifact("testdata/apipastries-postman-collection.json"),
+		WithRemoteGrpcEndpoint("grpc://microcks-grpc.example.com:443"),
+		WithMemoryLimit(0),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "secondary artifact testdata/apipastries-postman-collection.json needs a main artifact")
+	require.Contains(t, err.Error(), "a remote gRPC endpoint can only be used with Connect")
+	require.Contains(t, err.Error(), "error configuring memory limit")
+}
+
+func TestUnitContextCancellation(t *testing.T) {
+	container, err := Connect(context.Background(), "http://localhost:8080")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = container.WaitForInvocations(ctx, "API Pastries", "0.0.1", 1, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = container.ServiceInvocationStats(ctx, "API Pastries", "0.0.1")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUnitSharedStartupFailure(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		container, release, err := Shared(context.Background(), WithMemoryLimit(0))
+		require.Error(t, err)
+		require.Nil(t, container)
+		require.Nil(t, release)
+	}
+
+	// Failed startups are not cached, next call starts a new container.
+	sharedMutex.Lock()
+	defer sharedMutex.Unlock()
+	require.Nil(t, currentShared)
+}
+
+func TestUnitRequestModifier(t *testing.T) {
+	var files []testcontainers.ContainerFile
+	_, err := Run(context.Background(), DefaultImage,
+		WithSelfSignedTLS(),
+		WithRequestModifier(func(req *testcontainers.GenericContainerRequest) {
+			files = req.Files
+			// Prevents the container from being created.
+			req.Image = ""
+		}),
+	)
+	require.Error(t, err)
+
+	// Modifiers see the fully built request, including TLS files.
+	require.Len(t, files, 2)
+	require.Equal(t, tlsCertificatePath, files[0].ContainerFilePath)
+	require.Equal(t, int64(0o644), files[0].FileMode)
+	require.Equal(t, tlsPrivateKeyPath, files[1].ContainerFilePath)
+	require.Equal(t, int64(0o644), files[1].FileMode)
+}
+
+func TestUnitDebugLogging(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithDebugLogging().Customize(&req))
+	require.Equal(t, "DEBUG", req.Env["LOGGING_LEVEL_IO_GITHUB_MICROCKS"])
+}
+
+func TestUnitOpenTelemetry(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithOpenTelemetry("http://otel-collector:4317").Customize(&req))
+	require.Equal(t, "true", req.Env["OTEL_JAVA_GLOBAL_AUTOCONFIGURE_ENABLED"])
+	require.Equal(t, "http://otel-collector:4317", req.Env["OTEL_EXPORTER_OTLP_ENDPOINT"])
+	require.Equal(t, "tracecontext,baggage", req.Env["OTEL_PROPAGATORS"])
+
+	require.Error(t, WithOpenTelemetry("").Customize(&req))
+}
+
+func TestUnitEvents(t *testing.T) {
+	container := &MicrocksContainer{}
+	for i := 0; i < eventsBufferSize+1; i++ {
+		container.emit(Event{Type: EventArtifactImported, Subject: "apipastries-openapi.yaml"})
+	}
+
+	events := container.Events()
+	require.Len(t, events, eventsBufferSize)
+	event := <-events
+	require.Equal(t, EventArtifactImported, event.Type)
+	require.Equal(t, "apipastries-openapi.yaml", event.Subject)
+	require.False(t, event.Time.IsZero())
+
+	container.closeEvents()
+	container.emit(Event{Type: EventTestLaunched})
+	require.Len(t, events, eventsBufferSize-1)
+	for range events {
+	}
+}
+
+func TestUnitCollectDiagnostics(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case r.URL.Path == VersionPath:
+			_, _ = io.WriteString(w, `{"versionId":"1.9.0","buildTimestamp":"2024-03-19T10:43:21Z"}`)
+		case r.URL.Path == "/api/tests/service/1":
+			_, _ = io.WriteString(w, `[{"id":"t1","success":true}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	fileName, err := container.CollectDiagnostics(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	archive, err := zip.OpenReader(fileName)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	var entries []string
+	for _, f := range archive.File {
+		entries = append(entries, f.Name)
+	}
+	require.Contains(t, entries, "info.json")
+	require.Contains(t, entries, "services.json")
+	require.Contains(t, entries, "tests/API Pastries-0.0.1.json")
+	require.Contains(t, entries, "invocations/API Pastries-0.0.1.json")
+	require.NotContains(t, entries, "logs.txt")
+	require.NotContains(t, entries, "errors.txt")
+
+	info, err := container.Info(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "1.9.0", info.Version)
+	require.Empty(t, info.Image)
+}
+
+func TestUnitStartupReport(t *testing.T) {
+	clock := newStartupClock()
+	hooks := clock.hooks()
+	require.NoError(t, hooks.PreCreates[0](context.Background(), testcontainers.ContainerRequest{}))
+	for _, hook := range [][]testcontainers.ContainerHook{hooks.PostCreates, hooks.PostStarts, hooks.PostReadies} {
+		require.NoError(t, hook[0](context.Background(), nil))
+	}
+
+	container := &MicrocksContainer{startupClock: clock}
+	time.Sleep(10 * time.Millisecond)
+	container.lap("artifact apipastries-openapi.yaml")
+
+	report := container.StartupReport()
+	require.Len(t, report.Phases, 5)
+	require.Equal(t, "image pull", report.Phases[0].Name)
+	require.Equal(t, "container ready", report.Phases[3].Name)
+	require.Equal(t, "artifact apipastries-openapi.yaml", report.Phases[4].Name)
+	require.GreaterOrEqual(t, report.Phases[4].Duration, 10*time.Millisecond)
+	require.GreaterOrEqual(t, report.Total(), report.Phases[4].Duration)
+	require.Contains(t, report.String(), "total")
+
+	require.Empty(t, (&MicrocksContainer{}).StartupReport().Phases)
+}
+
+func TestUnitProgress(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	var progress []Progress
+	container, err := Connect(context.Background(), api.URL, WithProgress(func(p Progress) {
+		progress = append(progress, p)
+	}))
+	require.NoError(t, err)
+
+	statusCode, err := container.importSnapshot(context.Background(), "snapshot.json", strings.NewReader(`{"services":[]}`), 15)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	require.GreaterOrEqual(t, len(progress), 2)
+	first, last := progress[0], progress[len(progress)-1]
+	require.Equal(t, ProgressSnapshotUpload, first.Operation)
+	require.Equal(t, "snapshot.json", first.Subject)
+	require.Zero(t, first.SentBytes)
+	require.False(t, first.Done)
+	require.True(t, last.Done)
+	require.Positive(t, last.TotalBytes)
+	require.Equal(t, last.TotalBytes, last.SentBytes)
+
+	progress = nil
+	_, err = container.ImportRemoteArtifact(context.Background(), "https://example.com/openapi.yaml", true, "")
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+	require.Equal(t, ProgressArtifactImport, progress[1].Operation)
+	require.True(t, progress[1].Done)
+}
+
+func TestUnitProgressRetry(t *testing.T) {
+	var contentLengths []int64
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		contentLengths = append(contentLengths, r.ContentLength)
+		if len(contentLengths) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	artifact := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(artifact, []byte("openapi: 3.0.0\n"), 0o644))
+
+	var last Progress
+	container, err := Connect(context.Background(), api.URL,
+		WithProgress(func(p Progress) { last = p }),
+		WithRetryPolicy(apiclient.Backoff{MaxRetries: 1, ReplayNonIdempotent: true}),
+	)
+	require.NoError(t, err)
+
+	// Tracked uploads keep their size and can be replayed.
+	file, err := os.Open(artifact)
+	require.NoError(t, err)
+	defer file.Close()
+	reader := container.trackProgress(ProgressArtifactImport, artifact).upload(file, fileSize(file))
+	require.Equal(t, 15, reader.(interface{ Len() int }).Len())
+	_, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	_, err = reader.(io.Seeker).Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, 15, reader.(interface{ Len() int }).Len())
+
+	statusCode, err := container.importArtifact(context.Background(), artifact, true)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	require.Len(t, contentLengths, 2)
+	require.Positive(t, contentLengths[0])
+	require.Equal(t, contentLengths[0], contentLengths[1])
+	require.True(t, last.Done)
+	require.Equal(t, int64(15), last.SentBytes)
+	require.Equal(t, int64(15), last.TotalBytes)
+
+	// Readers that cannot seek are not replayable.
+	_, err = container.trackProgress(ProgressSnapshotUpload, "snapshot.json").upload(io.MultiReader(strings.NewReader("{}")), -1).(io.Seeker).Seek(0, io.SeekStart)
+	require.Error(t, err)
+}
+
+func TestUnitAPITracing(t *testing.T) {
+	var correlationID string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID = r.Header.Get(CorrelationIDHeader)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "imported")
+	}))
+	defer api.Close()
+
+	logger := &recordingLogger{}
+	container, err := Connect(context.Background(), api.URL, WithLogger(logger), WithAPITracing(false))
+	require.NoError(t, err)
+
+	_, err = container.ImportRemoteArtifact(context.Background(), "https://example.com/openapi.yaml", true, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, correlationID)
+	require.Len(t, logger.messages, 1)
+	require.Contains(t, logger.messages[0], "["+correlationID+"] Microcks API call POST /api/artifact/download: 201 in")
+
+	logger.messages = nil
+	t.Setenv(APITracingEnv, "bodies")
+	_, err = container.ImportRemoteArtifact(context.Background(), "https://example.com/openapi.yaml", true, "")
+	require.NoError(t, err)
+	require.Len(t, logger.messages, 3)
+	require.Contains(t, logger.messages[0], "url=https")
+	require.Contains(t, logger.messages[2], "imported")
+}
+
+func TestUnitSetDispatcher(t *testing.T) {
+	var updates []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case r.URL.Path == "/api/services/1":
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1","operations":[
+				{"name":"GET /pastries","dispatcher":"URI_PARAMS","dispatcherRules":"size","defaultDelay":100}]}`)
+		case r.URL.Path == "/api/services/1/operation" && r.Method == http.MethodPut:
+			require.Equal(t, "GET /pastries", r.URL.Query().Get("operationName"))
+			body, _ := io.ReadAll(r.Body)
+			updates = append(updates, string(body))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	dispatcher, rules, err := container.Dispatcher(context.Background(), "API Pastries", "0.0.1", "GET /pastries")
+	require.NoError(t, err)
+	require.Equal(t, apiclient.DispatcherURIParams, dispatcher)
+	require.Equal(t, "size", rules)
+
+	restore, err := container.SetDispatcher(context.Background(), "API Pastries", "0.0.1", "GET /pastries", apiclient.DispatcherScript, "return \"Large\"")
+	require.NoError(t, err)
+	require.NoError(t, restore(context.Background()))
+	require.Equal(t, []string{
+		`{"dispatcher":"SCRIPT","dispatcherRules":"return \"Large\"","defaultDelay":100}`,
+		`{"dispatcher":"URI_PARAMS","dispatcherRules":"size","defaultDelay":100}`,
+	}, updates)
+
+	_, err = container.SetDispatcher(context.Background(), "API Pastries", "0.0.1", "DELETE /pastries", apiclient.DispatcherScript, "")
+	require.Error(t, err)
+	_, _, err = container.Dispatcher(context.Background(), "API Orders", "1.0", "GET /orders")
+	require.Error(t, err)
+}
+
+func TestUnitOperationDelay(t *testing.T) {
+	delay := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case r.URL.Path == "/api/services/1":
+			fmt.Fprintf(w, `{"id":"1","name":"API Pastries","version":"0.0.1","operations":[{"name":"GET /pastries","defaultDelay":%d}]}`, delay)
+		case r.URL.Path == "/api/services/1/operation":
+			var override apiclient.OperationOverride
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&override))
+			delay = override.DefaultDelay
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	require.Error(t, container.SetOperationDelay(context.Background(), "API Pastries", "0.0.1", "GET /pastries", -1))
+	require.NoError(t, container.SetOperationDelay(context.Background(), "API Pastries", "0.0.1", "GET /pastries", 500))
+	require.Equal(t, 500, delay)
+	require.NoError(t, container.SetOperationDelay(context.Background(), "API Pastries", "0.0.1", "GET /pastries", 1000))
+	require.Equal(t, 1000, delay)
+
+	require.NoError(t, container.ResetOperationDelays(context.Background()))
+	require.Equal(t, 0, delay)
+}
+
+func TestUnitMessages(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case r.URL.Path == "/api/services/1" && r.URL.Query().Get("messages") == "true":
+			_, _ = io.WriteString(w, `{"service":{"id":"1"},"messagesMap":{"GET /pastries/{name}":[
+				{"request":{"name":"Millefeuille"},"response":{"name":"Millefeuille","status":"200","content":"{\"name\": \"Millefeuille\"}","dispatchCriteria":"/name=Millefeuille"}},
+				{"request":{"name":"Eclair"},"response":{"name":"Eclair","status":"200","content":"{\"name\": \"Eclair\"}","dispatchCriteria":"/name=Eclair"}}]}}`)
+		case r.URL.Path == "/api/services/1":
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1","operations":[{"name":"GET /pastries/{name}"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	messages, err := container.Messages(context.Background(), "API Pastries", "0.0.1", "GET /pastries/{name}")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, "Millefeuille", messages[0].Name())
+	require.Equal(t, "/name=Eclair", messages[1].Response.DispatchCriteria)
+
+	name, ok := MatchExample(messages, Exchange{StatusCode: http.StatusOK, ResponseBody: []byte(`{"name":"Eclair"}`)})
+	require.True(t, ok)
+	require.Equal(t, "Eclair", name)
+	_, ok = MatchExample(messages, Exchange{StatusCode: http.StatusNotFound, ResponseBody: []byte(`{"name":"Eclair"}`)})
+	require.False(t, ok)
+
+	_, err = container.Messages(context.Background(), "API Pastries", "0.0.1", "DELETE /pastries")
+	require.Error(t, err)
+}
+
+func TestUnitRetryPolicy(t *testing.T) {
+	failures := 2
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+	}))
+	defer api.Close()
+
+	_, err := Connect(context.Background(), api.URL, WithRetryPolicy(nil))
+	require.Error(t, err)
+
+	container, err := Connect(context.Background(), api.URL, WithRetryPolicy(apiclient.Backoff{MaxRetries: 2, InitialInterval: time.Millisecond}))
+	require.NoError(t, err)
+
+	services, err := container.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+}
+
+func TestUnitHTTPClient(t *testing.T) {
+	var userAgents []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		_, _ = io.WriteString(w, `[]`)
+	}))
+	defer api.Close()
+
+	_, err := Connect(context.Background(), api.URL, WithHTTPClient(nil))
+	require.Error(t, err)
+	_, err = Connect(context.Background(), api.URL, WithRoundTripper(nil))
+	require.Error(t, err)
+
+	var calls int
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	container, err := Connect(context.Background(), api.URL,
+		WithHTTPClient(httpClient),
+		WithRoundTripper(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				req = req.Clone(req.Context())
+				req.Header.Set("User-Agent", "instrumented")
+				return next.RoundTrip(req)
+			})
+		}),
+		WithAPITracing(false),
+	)
+	require.NoError(t, err)
+
+	userAgents = nil
+	calls = 0
+	_, err = container.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"instrumented"}, userAgents)
+	require.Equal(t, 1, calls)
+
+	apiClient := container.apiHTTPClient()
+	require.Equal(t, 5*time.Second, apiClient.Timeout)
+	require.Nil(t, httpClient.Transport)
+}
+
+// roundTripperFunc represents a function implementing http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestUnitSentinelErrors(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/artifact/upload":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "/api/services":
+			_, _ = io.WriteString(w, `[]`)
+		case "/api/tests":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"id":"t1","inProgress":true}`)
+		case "/api/tests/t1":
+			_, _ = io.WriteString(w, `{"id":"t1","inProgress":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	_, err := Connect(context.Background(), api.URL, WithMainArtifact("testdata/apipastries-openapi.yaml"))
+	require.ErrorIs(t, err, ErrArtifactRejected)
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	err = container.SetOperationDelay(context.Background(), "API Pastries", "0.0.1", "GET /pastries", 100)
+	require.ErrorIs(t, err, ErrServiceNotFound)
+
+	testResult, err := container.TestEndpoint(context.Background(), &apiclient.TestRequest{ServiceId: "API Pastries:0.0.1", Timeout: 300})
+	require.ErrorIs(t, err, ErrTestTimeout)
+	require.True(t, testResult.InProgress)
+}
+
+func TestUnitContextPropagation(t *testing.T) {
+	block := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/keycloak/config" {
+			_, _ = io.WriteString(w, `{"enabled":false}`)
+			return
+		}
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer api.Close()
+	defer close(block)
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	calls := map[string]func(ctx context.Context) error{
+		"ListServices": func(ctx context.Context) error {
+			_, err := container.ListServices(ctx)
+			return err
+		},
+		"ImportAsMainArtifact": func(ctx context.Context) error {
+			_, err := container.ImportAsMainArtifact(ctx, "testdata/apipastries-openapi.yaml")
+			return err
+		},
+		"ServiceInvocationsCount": func(ctx context.Context) error {
+			_, err := container.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+			return err
+		},
+		"SetOperationDelay": func(ctx context.Context) error {
+			return container.SetOperationDelay(ctx, "API Pastries", "0.0.1", "GET /pastries", 100)
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			require.ErrorIs(t, call(ctx), context.DeadlineExceeded)
+			require.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+// countingLimiter represents a rate limiter counting the calls it allows.
+type countingLimiter struct {
+	waits atomic.Int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits.Add(1)
+	return nil
+}
+
+func TestUnitRateLimiter(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[]`)
+	}))
+	defer api.Close()
+
+	_, err := Connect(context.Background(), api.URL, WithRateLimiter(nil))
+	require.Error(t, err)
+
+	limiter := &countingLimiter{}
+	container, err := Connect(context.Background(), api.URL, WithRateLimiter(limiter))
+	require.NoError(t, err)
+
+	before := limiter.waits.Load()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := container.ListServices(context.Background())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, before+10, limiter.waits.Load())
+}
+
+func TestUnitServiceCache(t *testing.T) {
+	var lookups atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/services":
+			lookups.Add(1)
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case "/api/services/1":
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1","operations":[{"name":"GET /pastries","dispatcher":"URI_PARAMS"}]}`)
+		case "/api/artifact/upload":
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, _, err := container.Dispatcher(context.Background(), "API Pastries", "0.0.1", "GET /pastries")
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), lookups.Load())
+
+	_, err = container.ImportAsMainArtifact(context.Background(), "testdata/apipastries-openapi.yaml")
+	require.NoError(t, err)
+	_, _, err = container.Dispatcher(context.Background(), "API Pastries", "0.0.1", "GET /pastries")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), lookups.Load())
+
+	container.InvalidateServiceCache()
+	_, _, err = container.Dispatcher(context.Background(), "API Pastries", "0.0.1", "GET /pastries")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), lookups.Load())
+}
+
+func TestUnitStaticCredentials(t *testing.T) {
+	var unauthorized []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gateway-token" || r.Header.Get("X-Gateway-Key") != "key" {
+			unauthorized = append(unauthorized, r.URL.Path)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/api/keycloak/config" {
+			_, _ = io.WriteString(w, `{"enabled":false}`)
+			return
+		}
+		_, _ = io.WriteString(w, `[]`)
+	}))
+	defer api.Close()
+
+	_, err := Connect(context.Background(), api.URL, WithBearerToken(""))
+	require.Error(t, err)
+	_, err = Connect(context.Background(), api.URL, WithAPIKey("X-Gateway-Key", ""))
+	require.Error(t, err)
+
+	container, err := Connect(context.Background(), api.URL,
+		WithBearerToken("gateway-token"),
+		WithAPIKey("X-Gateway-Key", "key"),
+		WithServiceAccount(api.URL, "microcks-serviceaccount", "secret"),
+	)
+	require.NoError(t, err)
+
+	_, err = container.ListServices(context.Background())
+	require.NoError(t, err)
+	_, err = container.Health(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, unauthorized)
+}
+
+func TestUnitExportResources(t *testing.T) {
+	resources := `[
+		{"name":"API Pastries-0.0.1.yaml","content":"openapi: 3.0.0","type":"OPEN_API_SPEC","mainArtifact":true},
+		{"name":"API Pastries-0.0.1-pastry.json","path":"./schemas/pastry.json","content":"{}","type":"JSON_SCHEMA"}]`
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case "/api/services/1":
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1"}`)
+		case "/api/resources/service/1":
+			_, _ = io.WriteString(w, resources)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	files, err := container.ExportResources(context.Background(), "API Pastries", "0.0.1", dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "API Pastries-0.0.1.yaml"),
+		filepath.Join(dir, "schemas", "pastry.json"),
+	}, files)
+	content, err := os.ReadFile(files[1])
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(content))
+
+	_, err = container.ExportResources(context.Background(), "API Orders", "1.0", dir)
+	require.ErrorIs(t, err, ErrServiceNotFound)
+
+	resources = `[{"name":"evil.json","path":"../../evil.json","content":"{}"}]`
+	_, err = container.ExportResources(context.Background(), "API Pastries", "0.0.1", dir)
+	require.Error(t, err)
+}
+
+func TestUnitOptionCustomize(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	err := WithServiceAccount("http://keycloak:8080", "microcks-serviceaccount", "secret").Customize(&req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "microcks.Option must be passed to microcks.Run or microcks.Connect")
+
+	// Artifacts and secrets fall back to lifecycle hooks outside of Run and Connect.
+	require.NoError(t, WithMainArtifact("testdata/apipastries-openapi.yaml").Customize(&req))
+	require.NoError(t, WithSecret(client.Secret{Name: "my-secret"}).Customize(&req))
+	require.Len(t, req.LifecycleHooks, 2)
+	require.Len(t, req.LifecycleHooks[0].PostReadies, 1)
+
+	// They are recorded otherwise, container customizers being told apart.
+	settings := options{}
+	stopRecording := settings.record(&req)
+	defer stopRecording()
+	require.NoError(t, WithSecondaryArtifact("testdata/apipastries-postman-collection.json").Customize(&req))
+	require.NoError(t, WithSecret(client.Secret{Name: "my-secret"}).Customize(&req))
+	require.Len(t, req.LifecycleHooks, 2)
+	require.Equal(t, []artifact{{path: "testdata/apipastries-postman-collection.json", main: false}}, settings.artifacts)
+	require.Len(t, settings.secrets, 1)
+
+	require.True(t, isRecordedOption(WithArtifact("testdata/apipastries-openapi.yaml", true)))
+	require.True(t, isRecordedOption(WithServiceAccount("http://keycloak:8080", "microcks-serviceaccount", "secret")))
+	require.False(t, isRecordedOption(WithHostAccessPorts([]int{8080})))
+	require.False(t, isRecordedOption(testcontainers.WithEnv(map[string]string{"KEY": "value"})))
+}
+
+func TestUnitServiceAccount(t *testing.T) {
+	var configCalls, tokenCalls atomic.Int32
+	enabled := false
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/keycloak/config":
+			configCalls.Add(1)
+			_, _ = fmt.Fprintf(w, `{"enabled":%t,"realm":"microcks"}`, enabled)
+		case "/realms/microcks/protocol/openid-connect/token":
+			tokenCalls.Add(1)
+			_, _ = io.WriteString(w, `{"access_token":"token","expires_in":300}`)
+		default:
+			_, _ = io.WriteString(w, `[]`)
+		}
+	}))
+	defer api.Close()
+
+	// Disabled authentication is only checked once.
+	container, err := Connect(context.Background(), api.URL,
+		WithServiceAccount(api.URL, "microcks-serviceaccount", "secret"),
+	)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = container.ListServices(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), configCalls.Load())
+
+	// Token requests go through the configured transport.
+	enabled = true
+	var requested []string
+	container, err = Connect(context.Background(), api.URL,
+		WithServiceAccount(api.URL, "microcks-serviceaccount", "secret"),
+		WithRoundTripper(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requested = append(requested, req.URL.Path)
+				return next.RoundTrip(req)
+			})
+		}),
+	)
+	require.NoError(t, err)
+	_, err = container.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), tokenCalls.Load())
+	require.Contains(t, requested, "/realms/microcks/protocol/openid-connect/token")
+}
+
+func TestUnitTrafficCapture(t *testing.T) {
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"name":"Millefeuille"}`)
+	}))
+	defer mock.Close()
+
+	target, err := url.Parse(mock.URL)
+	require.NoError(t, err)
+	capture := &TrafficCapture{target: target, maxExchanges: 2}
+	reverseProxy := httputil.NewSingleHostReverseProxy(target)
+	reverseProxy.ModifyResponse = capture.record
+	handler := captureHandler(reverseProxy)
+
+	call := func(method, path, body string) string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "http://localhost"+path, strings.NewReader(body)))
+		return rec.Body.String()
+	}
+
+	require.Equal(t, `{"name":"Millefeuille"}`, call(http.MethodGet, "/rest/API%20Pastries/0.0.1/pastries/Millefeuille", ""))
+	call(http.MethodPatch, "/rest/API%20Pastries/0.0.1/pastries/Millefeuille", `{"price":2.5}`)
+	call(http.MethodGet, "/rest/API%20Orders/1.0/orders", "")
+
+	// Oldest exchange has been evicted.
+	require.Len(t, capture.Exchanges("", ""), 2)
+	exchanges := capture.Exchanges(http.MethodPatch, "/rest/API Pastries/0.0.1/pastries")
+	require.Len(t, exchanges, 1)
+	require.Equal(t, `{"price":2.5}`, string(exchanges[0].RequestBody))
+	require.Equal(t, http.StatusOK, exchanges[0].StatusCode)
+	require.Equal(t, "application/json", exchanges[0].ResponseHeaders.Get("Content-Type"))
+	require.Equal(t, `{"name":"Millefeuille"}`, string(exchanges[0].ResponseBody))
+	require.Equal(t, "/rest/API Orders/1.0/orders", capture.Exchanges("", "")[0].Path)
+
+	requests := capture.ReceivedRequests("API Pastries", "0.0.1")
+	require.Len(t, requests, 1)
+	require.Equal(t, http.MethodPatch, requests[0].Method)
+	require.Equal(t, "/rest/API Pastries/0.0.1/pastries/Millefeuille", requests[0].URI)
+	require.Equal(t, `{"price":2.5}`, string(requests[0].Body))
+	require.NotNil(t, requests[0].Headers)
+	require.Empty(t, capture.ReceivedRequests("API Pastries", "0.0"))
+
+	capture.Reset()
+	require.Empty(t, capture.Exchanges("", ""))
+}
+
+func TestUnitSlowRequestThreshold(t *testing.T) {
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mock.Close()
+
+	target, err := url.Parse(mock.URL)
+	require.NoError(t, err)
+	var logs []string
+	capture := &TrafficCapture{target: target, maxExchanges: DefaultMaxExchanges, slowThreshold: 40 * time.Millisecond, logf: func(format string, args ...any) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}}
+	reverseProxy := httputil.NewSingleHostReverseProxy(target)
+	reverseProxy.ModifyResponse = capture.record
+	handler := captureHandler(reverseProxy)
+
+	for _, path := range []string{"/fast", "/slow"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+	}
+
+	slow := capture.SlowExchanges(0)
+	require.Len(t, slow, 1)
+	require.Equal(t, "/slow", slow[0].Path)
+	require.Len(t, capture.SlowExchanges(time.Nanosecond), 2)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "Slow mock request GET /slow")
+
+	_, err = Connect(context.Background(), "https://microcks.example.com", WithSlowRequestThreshold(0))
+	require.Error(t, err)
+}
+
+func TestUnitParseMockRequests(t *testing.T) {
+	logs := `2024-06-01 10:00:00.000  INFO 1 --- [nio-8080-exec-1] i.g.m.web.RestController : Servicing mock response for service [API Pastries, 0.0.1] on uri /rest/API%20Pastries/0.0.1/pastries/Millefeuille with verb GET
+2024-06-01 10:00:00.100 DEBUG 1 --- [nio-8080-exec-1] i.g.m.web.RestController : Dispatch criteria for finding response is /pastry=Millefeuille
+2024-06-01 10:00:01.000  INFO 1 --- [nio-8080-exec-2] i.g.m.web.RestController : Servicing mock response for service [Pastries, Cakes and Co, 1.0] on uri /rest/Pastries,%20Cakes%20and%20Co/1.0/cakes with verb POST
+2024-06-01 10:00:02.000  INFO 1 --- [nio-8080-exec-3] i.g.m.web.RestController : Servicing mock response for service [API Pastries, 0.0.1] on uri /rest/API%20Pastries/0.0.1/pastries?size=S with verb GET
+`
+	requests, err := parseMockRequests(strings.NewReader(logs), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, []MockRequest{
+		{ServiceName: "API Pastries", ServiceVersion: "0.0.1", Method: http.MethodGet, URI: "/rest/API Pastries/0.0.1/pastries/Millefeuille"},
+		{ServiceName: "API Pastries", ServiceVersion: "0.0.1", Method: http.MethodGet, URI: "/rest/API Pastries/0.0.1/pastries?size=S"},
+	}, requests)
+
+	// Service names holding ", " are split on the last one.
+	requests, err = parseMockRequests(strings.NewReader(logs), "Pastries, Cakes and Co", "1.0")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, http.MethodPost, requests[0].Method)
+	require.Equal(t, "/rest/Pastries, Cakes and Co/1.0/cakes", requests[0].URI)
+}
+
+func TestUnitOperationInvocations(t *testing.T) {
+	service := &apiclient.Service{Name: "API Pastries", Version: "0.0.1", Operations: []apiclient.Operation{
+		{Name: "GET /pastries"},
+		{Name: "GET /pastries/{name}"},
+		{Name: "GET /pastries/new"},
+		{Name: "PATCH /pastries/:name"},
+		{Name: "allPastries"},
+	}}
+	for uri, expected := range map[string]string{
+		"/rest/API Pastries/0.0.1/pastries?size=S":          "GET /pastries",
+		"/rest/API Pastries/0.0.1/pastries/":                "GET /pastries",
+		"/rest/API Pastries/0.0.1/pastries/Millefeuille":    "GET /pastries/{name}",
+		"/rest/API Pastries/0.0.1/pastries/new":             "GET /pastries/new",
+		"/rest/API Pastries/0.0.1/pastries/Millefeuille/id": "",
+		"/rest/API Orders/1.0/orders":                       "",
+	} {
+		name, ok := matchOperation(service, http.MethodGet, uri)
+		require.Equal(t, expected != "", ok, uri)
+		require.Equal(t, expected, name, uri)
+	}
+	name, ok := matchOperation(service, http.MethodPatch, "/rest/API Pastries/0.0.1/pastries/Eclair")
+	require.True(t, ok)
+	require.Equal(t, "PATCH /pastries/:name", name)
+	_, ok = matchOperation(service, http.MethodDelete, "/rest/API Pastries/0.0.1/pastries/Eclair")
+	require.False(t, ok)
+}
+
+func TestUnitOperationStatusCodes(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case "/api/services/1":
+			_, _ = io.WriteString(w, `{"id":"1","name":"API Pastries","version":"0.0.1","operations":[{"name":"GET /pastries/{name}"},{"name":"DELETE /pastries/{name}"}]}`)
+		case "/rest/API Pastries/0.0.1/pastries/Unknown":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = io.WriteString(w, `{}`)
+		}
+	}))
+	defer api.Close()
+
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+	capture, err := container.StartTrafficCapture(context.Background(), 0)
+	require.NoError(t, err)
+	defer capture.Close(context.Background())
+
+	for _, path := range []string{"/rest/API%20Pastries/0.0.1/pastries/Millefeuille", "/rest/API%20Pastries/0.0.1/pastries/Eclair", "/rest/API%20Pastries/0.0.1/pastries/Unknown", "/rest/API%20Orders/1.0/orders"} {
+		response, err := http.Get(capture.URL() + path)
+		require.NoError(t, err)
+		response.Body.Close()
+	}
+
+	statusCodes, err := capture.OperationStatusCodes(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[int]int{
+		"GET /pastries/{name}":    {http.StatusOK: 2, http.StatusNotFound: 1},
+		"DELETE /pastries/{name}": {},
+	}, statusCodes)
+
+	_, err = capture.OperationStatusCodes(context.Background(), "API Orders", "1.0")
+	require.Error(t, err)
+}
+
+// fakeContainer represents a started container whose Microcks HTTP port is served by given URL.
+type fakeContainer struct {
+	testcontainers.Container
+	url *url.URL
+}
+
+func (c *fakeContainer) Host(ctx context.Context) (string, error) {
+	return c.url.Hostname(), nil
+}
+
+func (c *fakeContainer) MappedPort(ctx context.Context, port nat.Port) (nat.Port, error) {
+	return nat.NewPort("tcp", c.url.Port())
+}
+
+// invocationsServer represents a Microcks API serving the daily invocation statistics of API Pastries 0.0.1.
+type invocationsServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	counts    map[string]int
+	emptyBody bool
+}
+
+func newInvocationsServer(t *testing.T) *invocationsServer {
+	s := &invocationsServer{counts: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/services":
+			_, _ = io.WriteString(w, `[{"id":"1","name":"API Pastries","version":"0.0.1"}]`)
+		case "/api/metrics/invocations/API Pastries/0.0.1":
+			day := r.URL.Query().Get("day")
+			s.mutex.Lock()
+			count, ok := s.counts[day]
+			emptyBody := s.emptyBody
+			s.mutex.Unlock()
+			if !ok {
+				if !emptyBody {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"serviceName":"API Pastries","serviceVersion":"0.0.1","day":%q,"dailyCount":%d}`, day, count)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// set sets the invocations count of given day, days ago.
+func (s *invocationsServer) set(daysAgo int, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.counts[time.Now().UTC().AddDate(0, 0, -daysAgo).Format(statsDayLayout)] = count
+}
+
+// setEmptyBody sets whether days without statistics are served as an empty body rather than as not found.
+func (s *invocationsServer) setEmptyBody(emptyBody bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.emptyBody = emptyBody
+}
+
+// container returns a MicrocksContainer backed by the server.
+func (s *invocationsServer) container(t *testing.T) *MicrocksContainer {
+	serverURL, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	return &MicrocksContainer{Container: &fakeContainer{url: serverURL}, statsSince: time.Now()}
+}
+
+func TestUnitInvocationsDayBoundary(t *testing.T) {
+	api := newInvocationsServer(t)
+	container := api.container(t)
+
+	// Right after UTC midnight, invocations of the previous day are still counted.
+	container.statsSince = time.Now().AddDate(0, 0, -1)
+	api.set(1, 5)
+	count, err := container.ServiceInvocationsCount(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+	invoked, err := container.Verify(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.True(t, invoked)
+
+	// Every day since the container started is counted, including days without statistics.
+	container.statsSince = time.Now().AddDate(0, 0, -3)
+	api.set(3, 1)
+	api.set(0, 2)
+	count, err = container.ServiceInvocationsCount(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 8, count)
+
+	// Days before the baseline are not counted, the baseline day is counted from the baseline.
+	container.statsBaseline = map[string]*InvocationStats{
+		"API Pastries:0.0.1": {Day: time.Now().UTC().AddDate(0, 0, -1).Format(statsDayLayout), DailyCount: 3},
+	}
+	count, err = container.ServiceInvocationsCount(context.Background(), "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+}
+
+func TestUnitInvocationStats(t *testing.T) {
+	api := newInvocationsServer(t)
+	container, err := Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// A Service never invoked has no statistics.
+	invoked, err := container.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.False(t, invoked)
+	count, err := container.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// Missing statistics may also be served as an empty body.
+	api.setEmptyBody(true)
+	invoked, err = container.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.False(t, invoked)
+
+	api.set(0, 3)
+	invoked, err = container.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.True(t, invoked)
+	count, err = container.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.NoError(t, container.WaitForInvocations(ctx, "API Pastries", "0.0.1", 3, time.Second))
+
+	// Counts restart from zero after a reset.
+	require.NoError(t, container.ResetInvocationStats(ctx))
+	invoked, err = container.Verify(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.False(t, invoked)
+
+	api.set(0, 5)
+	count, err = container.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	stats, err := container.ServiceInvocationStats(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.DailyCount)
+
+	// Probes count from their start, regardless of resets.
+	probe, err := container.StartProbe(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	api.set(0, 9)
+	delta, err := probe.Delta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 4, delta)
+	count, err = container.ServiceInvocationsCount(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 6, count)
+}
+
+func TestUnitSubtractStats(t *testing.T) {
+	stats := &InvocationStats{Day: "20240601", DailyCount: 5, HourlyCount: map[string]int{"10": 5}, MinuteCount: map[string]int{"600": 2, "601": 3}}
+	subtractStats(stats, &InvocationStats{Day: "20240601", DailyCount: 2, HourlyCount: map[string]int{"10": 2}, MinuteCount: map[string]int{"600": 2}})
+	require.Equal(t, 3, stats.DailyCount)
+	require.Equal(t, map[string]int{"10": 3}, stats.HourlyCount)
+	require.Equal(t, map[string]int{"600": 0, "601": 3}, stats.MinuteCount)
+
+	// A baseline of another day does not apply.
+	subtractStats(stats, &InvocationStats{Day: "20240531", DailyCount: 1})
+	require.Equal(t, 3, stats.DailyCount)
+}
@@ -16,25 +16,43 @@
 package microcks_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
 
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"microcks.io/go-client"
 	microcks "microcks.io/testcontainers-go"
+	"microcks.io/testcontainers-go/health"
 	"microcks.io/testcontainers-go/internal/test"
+	"microcks.io/testcontainers-go/microckstest"
 )
 
 func TestMockingFunctionalityAtStartup(t *testing.T) {
 	ctx := context.Background()
 
-	microcksContainer, err := microcks.RunContainer(ctx,
-		testcontainers.WithImage("quay.io/microcks/microcks-uber:nightly"),
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithTmpfsStorage(),
+		microcks.WithWaitStrategy(health.WaitStrategy(microcks.HealthPath, microcks.DefaultHttpPort)),
 		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
 		microcks.WithSecondaryArtifact("testdata/apipastries-postman-collection.json"),
 	)
@@ -45,16 +63,131 @@ func TestMockingFunctionalityAtStartup(t *testing.T) {
 		}
 	})
 
+	require.True(t, microcksContainer.IsReady(ctx))
+
+	info, err := microcksContainer.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "quay.io/microcks/microcks-uber:nightly", info.Image)
+	require.NotEmpty(t, info.ImageDigest)
+	require.NotEmpty(t, info.Version)
+
 	test.ConfigRetrieval(t, ctx, microcksContainer)
+	test.ServicesRetrieval(t, ctx, microcksContainer)
 	test.MockEndpoints(t, ctx, microcksContainer)
 
 	test.MicrocksMockingFunctionality(t, ctx, microcksContainer)
 }
 
+func TestTestingRunFunctionality(t *testing.T) {
+	microcksContainer := microckstest.Run(t,
+		testcontainers.WithImage("quay.io/microcks/microcks-uber:nightly"),
+		microcks.WithTmpfsStorage(),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+
+	ctx := context.Background()
+	require.True(t, microcksContainer.IsReady(ctx))
+	test.ConfigRetrieval(t, ctx, microcksContainer)
+}
+
+// pastriesSuite represents a testify suite using the API Pastries mocks.
+type pastriesSuite struct {
+	microckstest.Suite
+}
+
+func (s *pastriesSuite) TestMockEndpoints() {
+	test.MockEndpoints(s.T(), context.Background(), s.Microcks)
+}
+
+func (s *pastriesSuite) TestMockingFunctionality() {
+	test.MicrocksMockingFunctionality(s.T(), context.Background(), s.Microcks)
+}
+
+func TestSuiteFunctionality(t *testing.T) {
+	suite.Run(t, &pastriesSuite{Suite: microckstest.Suite{
+		Options: []testcontainers.ContainerCustomizer{
+			testcontainers.WithImage("quay.io/microcks/microcks-uber:nightly"),
+			microcks.WithTmpfsStorage(),
+			microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+			microcks.WithSecondaryArtifact("testdata/apipastries-postman-collection.json"),
+		},
+	}})
+}
+
+func TestReuseFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithReuse("microcks-reuse-test"),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	reusedContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithReuse("microcks-reuse-test"),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, microcksContainer.GetContainerID(), reusedContainer.GetContainerID())
+
+	test.ConfigRetrieval(t, ctx, reusedContainer)
+}
+
+func TestSharedFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	containerIDs := make(chan string, 2)
+	t.Run("group", func(t *testing.T) {
+		for _, name := range []string{"first", "second"} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				microcksContainer, release, err := microcks.Shared(ctx,
+					microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+				)
+				require.NoError(t, err)
+				t.Cleanup(func() {
+					require.NoError(t, release(ctx))
+				})
+
+				containerIDs <- microcksContainer.GetContainerID()
+				test.MockEndpoints(t, ctx, microcksContainer)
+			})
+		}
+	})
+
+	// Both tests used the same container.
+	require.Equal(t, <-containerIDs, <-containerIDs)
+}
+
+func TestFollowLogsFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// Logs are streamed from the container start until the context is done.
+	followCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	var logs bytes.Buffer
+	require.NoError(t, microcksContainer.FollowLogs(followCtx, &logs))
+	require.Contains(t, logs.String(), "Started MicrocksApplication")
+}
+
 func TestMockingFunctionality(t *testing.T) {
 	ctx := context.Background()
 
-	microcksContainer, err := microcks.RunContainer(ctx, testcontainers.WithImage("quay.io/microcks/microcks-uber:nightly"))
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly")
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		if err := microcksContainer.Terminate(ctx); err != nil {
@@ -92,8 +225,7 @@ func TestContractTestingFunctionality(t *testing.T) {
 		_ = network.Remove(ctx)
 	}()
 
-	microcksContainer, err := microcks.RunContainer(ctx,
-		testcontainers.WithImage("quay.io/microcks/microcks-uber:nightly"),
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
 		microcks.WithNetwork(networkName),
 	)
 	require.NoError(t, err)
@@ -153,6 +285,81 @@ func TestContractTestingFunctionality(t *testing.T) {
 	test.PrintMicrocksContainerLogs(t, ctx, microcksContainer)
 }
 
+func TestDockerNetworkFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err, "cannot create network")
+	t.Cleanup(func() {
+		_ = nw.Remove(ctx)
+	})
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithDockerNetwork(nw),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+
+	goodImplReq := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:      "quay.io/microcks/contract-testing-demo:02",
+			WaitingFor: wait.ForLog("Example app listening on port 3002"),
+		},
+		Started: true,
+	}
+	require.NoError(t, network.WithNetwork([]string{"good-impl"}, nw).Customize(&goodImplReq))
+	goodImpl, err := testcontainers.GenericContainer(ctx, goodImplReq)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+		if err := goodImpl.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	test.AssertGoodImplementation(t, ctx, microcksContainer)
+}
+
+func TestHostAccessFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	// Application under test running on the host.
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithHostAccess(port),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	testRequest := client.TestRequest{
+		ServiceId:    "API Pastries:0.0.1",
+		RunnerType:   client.TestRunnerTypeOPENAPISCHEMA,
+		TestEndpoint: microcks.HostEndpoint(port),
+		Timeout:      2000,
+	}
+	testResult, err := microcksContainer.TestEndpoint(ctx, &testRequest)
+	require.NoError(t, err)
+	require.Equal(t, microcks.HostEndpoint(port), testResult.TestedEndpoint)
+	require.NotEmpty(t, testResult.TestCaseResults)
+}
+
 func TestSecretFunctionality(t *testing.T) {
 	ctx := context.Background()
 
@@ -161,8 +368,7 @@ func TestSecretFunctionality(t *testing.T) {
 		Description: "test-secret",
 	}
 
-	microcksContainer, err := microcks.RunContainer(ctx,
-		testcontainers.WithImage("quay.io/microcks/microcks-uber:nightly"),
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
 		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
 		microcks.WithSecret(s),
 	)
@@ -178,4 +384,169 @@ func TestSecretFunctionality(t *testing.T) {
 	id := "test-secret"
 	s.Id = &id
 	test.SecretRetrieval(t, ctx, microcksContainer, &s)
+
+	// Rotate secret at runtime.
+	username := "test-user"
+	rotated, err := microcksContainer.CreateSecret(ctx, client.Secret{
+		Name:        "rotated-secret",
+		Description: "rotated-secret",
+		Username:    &username,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rotated.Id)
+
+	rotated.Description = "rotated-secret-v2"
+	err = microcksContainer.UpdateSecret(ctx, *rotated.Id, *rotated)
+	require.NoError(t, err)
+
+	secrets, err := microcksContainer.Secrets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(secrets))
+
+	err = microcksContainer.DeleteSecret(ctx, *rotated.Id)
+	require.NoError(t, err)
+
+	secrets, err = microcksContainer.Secrets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(secrets))
+}
+
+func TestTLSFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithSelfSignedTLS(),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	endpoint, err := microcksContainer.HttpEndpoint(ctx)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(endpoint, "https://"))
+	require.NotNil(t, microcksContainer.CACertificate())
+
+	baseApiUrl, err := microcksContainer.RestMockEndpoint(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+
+	// Call mock trusting Microcks CA.
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: microcksContainer.CertPool()},
+		},
+	}
+	resp, err := httpClient.Get(baseApiUrl + "/pastries/Millefeuille")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestTLSFilesFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	// Private key is readable by its owner only, as usual, and must still be readable by Microcks.
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	certificate, privateKey := generateCertificate(t)
+	require.NoError(t, os.WriteFile(certFile, certificate, 0o644))
+	require.NoError(t, os.WriteFile(keyFile, privateKey, 0o600))
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithTLS(certFile, keyFile, ""),
+		microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	baseApiUrl, err := microcksContainer.RestMockEndpoint(ctx, "API Pastries", "0.0.1")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(baseApiUrl, "https://"))
+
+	// Call mock trusting given certificate, whatever the Docker host is.
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: microcksContainer.CertPool(), ServerName: "localhost"},
+		},
+	}
+	resp, err := httpClient.Get(baseApiUrl + "/pastries/Millefeuille")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate for localhost and its private key.
+func generateCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestGrpcTLSFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithSelfSignedGrpcTLS(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	grpcEndpoint, err := microcksContainer.GrpcMockEndpoint(ctx)
+	require.NoError(t, err)
+
+	// TLS handshake must succeed trusting the gRPC CA.
+	tlsConfig := microcksContainer.GrpcTLSConfig()
+	require.NotNil(t, tlsConfig)
+	tlsConfig.NextProtos = []string{"h2"}
+	conn, err := tls.Dial("tcp", strings.TrimPrefix(grpcEndpoint, "grpc://"), tlsConfig)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestRemoteArtifactFunctionality(t *testing.T) {
+	ctx := context.Background()
+
+	microcksContainer, err := microcks.Run(ctx, "quay.io/microcks/microcks-uber:nightly",
+		microcks.WithMainRemoteArtifact("https://raw.githubusercontent.com/microcks/microcks/master/samples/APIPastry-openapi.yaml"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := microcksContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	baseApiUrl, err := microcksContainer.RestMockEndpoint(ctx, "API Pastry - 2.0", "2.0.0")
+	require.NoError(t, err)
+
+	resp, err := http.Get(baseApiUrl + "/pastry/Millefeuille")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
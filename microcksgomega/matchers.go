@@ -0,0 +1,176 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package microcksgomega provides Gomega matchers for Microcks contract tests and mock invocations, so that Ginkgo
+// and Gomega users can express assertions idiomatically:
+//
+//	Expect(testResult).To(microcksgomega.BeConformant())
+//	Eventually(microcksContainer).Should(microcksgomega.HaveInvokedService("API Pastries", "0.0.1"))
+//
+// Matchers implement the types.GomegaMatcher interface without depending on Gomega.
+package microcksgomega
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"microcks.io/go-client"
+	microcks "microcks.io/testcontainers-go"
+)
+
+// Matcher represents a matcher implementing the types.GomegaMatcher interface of Gomega.
+type Matcher interface {
+	// Match tells if actual matches.
+	Match(actual any) (bool, error)
+
+	// FailureMessage returns the message of a failed Should or To assertion.
+	FailureMessage(actual any) string
+
+	// NegatedFailureMessage returns the message of a failed ShouldNot or NotTo assertion.
+	NegatedFailureMessage(actual any) string
+}
+
+// BeConformant succeeds when actual, a *client.TestResult or client.TestResult as returned by TestEndpoint, is
+// finished and successful. The failure message holds the messages of the failed test steps.
+func BeConformant() Matcher {
+	return &conformantMatcher{}
+}
+
+// conformantMatcher represents the BeConformant matcher.
+type conformantMatcher struct{}
+
+func (m *conformantMatcher) Match(actual any) (bool, error) {
+	testResult, err := toTestResult(actual)
+	if err != nil {
+		return false, err
+	}
+	return !testResult.InProgress && testResult.Success, nil
+}
+
+func (m *conformantMatcher) FailureMessage(actual any) string {
+	testResult, err := toTestResult(actual)
+	if err != nil {
+		return err.Error()
+	}
+	if testResult.InProgress {
+		return fmt.Sprintf("Expected test %s of %s to be conformant, but it is still in progress", testResult.Id, testResult.TestedEndpoint)
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "Expected test %s of %s to be conformant, but it failed", testResult.Id, testResult.TestedEndpoint)
+	for _, stepMessage := range failedStepMessages(testResult) {
+		fmt.Fprintf(&message, "\n  - %s", stepMessage)
+	}
+	return message.String()
+}
+
+func (m *conformantMatcher) NegatedFailureMessage(actual any) string {
+	testResult, err := toTestResult(actual)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("Expected test %s of %s not to be conformant, but it succeeded", testResult.Id, testResult.TestedEndpoint)
+}
+
+// toTestResult converts actual to a test result.
+func toTestResult(actual any) (*client.TestResult, error) {
+	switch testResult := actual.(type) {
+	case *client.TestResult:
+		if testResult == nil {
+			return nil, fmt.Errorf("BeConformant expects a non-nil *client.TestResult")
+		}
+		return testResult, nil
+	case client.TestResult:
+		return &testResult, nil
+	}
+	return nil, fmt.Errorf("BeConformant expects a *client.TestResult or client.TestResult, actual %T", actual)
+}
+
+// failedStepMessages returns the messages of the steps of the failed test cases of testResult.
+func failedStepMessages(testResult *client.TestResult) []string {
+	var messages []string
+	if testResult.TestCaseResults == nil {
+		return messages
+	}
+	for _, testCase := range *testResult.TestCaseResults {
+		if testCase.Success || testCase.TestStepResults == nil {
+			continue
+		}
+		for _, step := range *testCase.TestStepResults {
+			if step.Message != nil && *step.Message != "" {
+				messages = append(messages, *step.Message)
+			}
+		}
+	}
+	return messages
+}
+
+// HaveInvokedService succeeds when the Service having given name and version has been invoked at least once, for
+// the current invocations statistics of actual, a *microcks.MicrocksContainer. Use it with Eventually to wait for
+// asynchronous invocations.
+func HaveInvokedService(serviceName, serviceVersion string) Matcher {
+	return &invocationsMatcher{serviceName: serviceName, serviceVersion: serviceVersion, times: -1}
+}
+
+// HaveInvokedServiceTimes succeeds when the Service having given name and version has been invoked exactly times
+// times, for the current invocations statistics of actual, a *microcks.MicrocksContainer.
+func HaveInvokedServiceTimes(serviceName, serviceVersion string, times int) Matcher {
+	return &invocationsMatcher{serviceName: serviceName, serviceVersion: serviceVersion, times: times}
+}
+
+// invocationsMatcher represents the HaveInvokedService and HaveInvokedServiceTimes matchers. times is -1 for at
+// least once.
+type invocationsMatcher struct {
+	serviceName    string
+	serviceVersion string
+	times          int
+
+	// count represents the invocations count of the last match.
+	count int
+}
+
+func (m *invocationsMatcher) Match(actual any) (bool, error) {
+	container, ok := actual.(*microcks.MicrocksContainer)
+	if !ok || container == nil {
+		return false, fmt.Errorf("HaveInvokedService expects a non-nil *microcks.MicrocksContainer, actual %T", actual)
+	}
+
+	count, err := container.ServiceInvocationsCount(context.Background(), m.serviceName, m.serviceVersion)
+	if err != nil {
+		return false, err
+	}
+	m.count = count
+	if m.times < 0 {
+		return count > 0, nil
+	}
+	return count == m.times, nil
+}
+
+func (m *invocationsMatcher) FailureMessage(actual any) string {
+	return fmt.Sprintf("Expected service %s:%s to have been invoked %s, but it has been invoked %d times", m.serviceName, m.serviceVersion, m.expected(), m.count)
+}
+
+func (m *invocationsMatcher) NegatedFailureMessage(actual any) string {
+	return fmt.Sprintf("Expected service %s:%s not to have been invoked %s, but it has been invoked %d times", m.serviceName, m.serviceVersion, m.expected(), m.count)
+}
+
+// expected describes the expected invocations count.
+func (m *invocationsMatcher) expected() string {
+	if m.times < 0 {
+		return "at least once"
+	}
+	return fmt.Sprintf("%d times", m.times)
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcksgomega
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"microcks.io/go-client"
+	microcks "microcks.io/testcontainers-go"
+)
+
+func TestBeConformant(t *testing.T) {
+	message := "Response status code is 500"
+	failed := &client.TestResult{
+		Id:             "test-1",
+		TestedEndpoint: "http://pastries:8080",
+		TestCaseResults: &[]client.TestCaseResult{
+			{Success: true},
+			{TestStepResults: &[]client.TestStepResult{{Message: &message}}},
+		},
+	}
+
+	matcher := BeConformant()
+	ok, err := matcher.Match(failed)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "Expected test test-1 of http://pastries:8080 to be conformant, but it failed\n  - Response status code is 500", matcher.FailureMessage(failed))
+
+	ok, err = matcher.Match(client.TestResult{Id: "test-2", Success: true})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	inProgress := &client.TestResult{Id: "test-3", Success: true, InProgress: true}
+	ok, err = matcher.Match(inProgress)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, matcher.FailureMessage(inProgress), "still in progress")
+
+	_, err = matcher.Match("not a test result")
+	require.Error(t, err)
+	_, err = matcher.Match((*client.TestResult)(nil))
+	require.Error(t, err)
+}
+
+func TestHaveInvokedService(t *testing.T) {
+	var count atomic.Int32
+	var emptyBody atomic.Bool
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/keycloak/config":
+			_, _ = io.WriteString(w, `{"enabled":false}`)
+		case strings.HasPrefix(r.URL.Path, "/api/metrics/invocations/API Pastries/0.0.1"):
+			if count.Load() == 0 {
+				if !emptyBody.Load() {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return
+			}
+			day := r.URL.Query().Get("day")
+			_, _ = io.WriteString(w, `{"serviceName":"API Pastries","serviceVersion":"0.0.1","day":"`+day+`","dailyCount":`+strconv.Itoa(int(count.Load()))+`}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	container, err := microcks.Connect(context.Background(), api.URL)
+	require.NoError(t, err)
+
+	invoked := HaveInvokedService("API Pastries", "0.0.1")
+	ok, err := invoked.Match(container)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "Expected service API Pastries:0.0.1 to have been invoked at least once, but it has been invoked 0 times", invoked.FailureMessage(container))
+
+	// Missing statistics may also be served as an empty body.
+	emptyBody.Store(true)
+	ok, err = invoked.Match(container)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	count.Store(2)
+	ok, err = invoked.Match(container)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	twice := HaveInvokedServiceTimes("API Pastries", "0.0.1", 2)
+	ok, err = twice.Match(container)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	thrice := HaveInvokedServiceTimes("API Pastries", "0.0.1", 3)
+	ok, err = thrice.Match(container)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "Expected service API Pastries:0.0.1 to have been invoked 3 times, but it has been invoked 2 times", thrice.FailureMessage(container))
+
+	_, err = invoked.Match(nil)
+	require.Error(t, err)
+}
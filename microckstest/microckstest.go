@@ -0,0 +1,108 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microckstest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// dumpLogsTimeout represents the maximum time spent dumping logs, so that a stuck Docker daemon does not hang the
+// test cleanup.
+const dumpLogsTimeout = 30 * time.Second
+
+// DumpLogsOnFailure registers a cleanup writing the full logs of container to the test log when the test failed,
+// so that CI failures come with the evidence attached. Cleanups run in reverse order: call it once the container
+// termination is registered, so that logs are dumped before the container is removed.
+func DumpLogsOnFailure(t testing.TB, container testcontainers.Container) {
+	t.Helper()
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), dumpLogsTimeout)
+		defer cancel()
+
+		var logs strings.Builder
+		if err := dumpLogs(ctx, container, &logs); err != nil {
+			t.Logf("Unable to dump logs of container %s: %s", shortID(container), err)
+			return
+		}
+		t.Logf("Logs of container %s:\n%s", shortID(container), logs.String())
+	})
+}
+
+// DumpLogsOnFailureToDir registers a cleanup writing the full logs of container to a file of dir when the test
+// failed, e.g. a directory collected as CI artifacts. The file is named after the test and the container.
+func DumpLogsOnFailureToDir(t testing.TB, container testcontainers.Container, dir string) {
+	t.Helper()
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+
+		fileName := filepath.Join(dir, fmt.Sprintf("%s-%s.log", strings.ReplaceAll(t.Name(), "/", "_"), shortID(container)))
+		ctx, cancel := context.WithTimeout(context.Background(), dumpLogsTimeout)
+		defer cancel()
+
+		if err := dumpLogsToFile(ctx, container, fileName); err != nil {
+			t.Logf("Unable to dump logs of container %s: %s", shortID(container), err)
+			return
+		}
+		t.Logf("Logs of container %s written to %s", shortID(container), fileName)
+	})
+}
+
+func dumpLogsToFile(ctx context.Context, container testcontainers.Container, fileName string) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return dumpLogs(ctx, container, file)
+}
+
+func dumpLogs(ctx context.Context, container testcontainers.Container, w io.Writer) error {
+	logs, err := container.Logs(ctx)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	_, err = io.Copy(w, logs)
+	return err
+}
+
+func shortID(container testcontainers.Container) string {
+	id := container.GetContainerID()
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
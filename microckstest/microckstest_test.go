@@ -0,0 +1,113 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microckstest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	microcks "microcks.io/testcontainers-go"
+)
+
+type fakeContainer struct {
+	testcontainers.Container
+}
+
+func (c *fakeContainer) GetContainerID() string {
+	return "0123456789abcdef"
+}
+
+func (c *fakeContainer) Logs(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("Started MicrocksApplication\n")), nil
+}
+
+// fakeT records cleanups and logs of a test, failed or not.
+type fakeT struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (t *fakeT) Helper()                 {}
+func (t *fakeT) Name() string            { return "TestOrders/create" }
+func (t *fakeT) Failed() bool            { return t.failed }
+func (t *fakeT) Cleanup(f func())        { t.cleanups = append(t.cleanups, f) }
+func (t *fakeT) Logf(f string, a ...any) { t.logs = append(t.logs, fmt.Sprintf(f, a...)) }
+
+// Fatalf records the failure and stops the calling goroutine, as testing.T does.
+func (t *fakeT) Fatalf(f string, a ...any) {
+	t.failed = true
+	t.Logf(f, a...)
+	runtime.Goexit()
+}
+
+func (t *fakeT) runCleanups() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestDumpLogsOnFailure(t *testing.T) {
+	passed := &fakeT{}
+	DumpLogsOnFailure(passed, &fakeContainer{})
+	passed.runCleanups()
+	require.Empty(t, passed.logs)
+
+	failed := &fakeT{failed: true}
+	DumpLogsOnFailure(failed, &fakeContainer{})
+	failed.runCleanups()
+	require.Len(t, failed.logs, 1)
+	require.Contains(t, failed.logs[0], "Logs of container 0123456789ab")
+	require.Contains(t, failed.logs[0], "Started MicrocksApplication")
+}
+
+func TestDumpLogsOnFailureToDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "artifacts")
+
+	failed := &fakeT{failed: true}
+	DumpLogsOnFailureToDir(failed, &fakeContainer{}, dir)
+	failed.runCleanups()
+
+	logs, err := os.ReadFile(filepath.Join(dir, "TestOrders_create-0123456789ab.log"))
+	require.NoError(t, err)
+	require.Equal(t, "Started MicrocksApplication\n", string(logs))
+}
+
+func TestRunFailure(t *testing.T) {
+	failed := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(failed, microcks.WithStartupTimeout(-time.Second))
+		t.Error("Run returned despite the startup failure")
+	}()
+	<-done
+
+	require.True(t, failed.failed)
+	require.Len(t, failed.logs, 1)
+	require.Contains(t, failed.logs[0], "Unable to start Microcks: error configuring startup timeout")
+	require.Empty(t, failed.cleanups)
+}
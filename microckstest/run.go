@@ -0,0 +1,72 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microckstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	microcks "microcks.io/testcontainers-go"
+)
+
+// terminateTimeout represents the maximum time spent terminating the container in the test cleanup.
+const terminateTimeout = 30 * time.Second
+
+// Run starts a Microcks container of microcks.DefaultImage for the test, failing it with the startup error if any.
+// Startup is bounded by the test deadline, if any.
+// The container module messages and lifecycle are logged to the test log, its logs are dumped when the test failed,
+// and it is terminated once the test and its subtests complete. Options are the ones of microcks.Run: use
+// testcontainers.WithImage to run another image, and microcks.WithLogger to log elsewhere.
+func Run(t testing.TB, opts ...testcontainers.ContainerCustomizer) *microcks.MicrocksContainer {
+	t.Helper()
+
+	ctx := context.Background()
+	if deadline, ok := testDeadline(t); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	opts = append([]testcontainers.ContainerCustomizer{microcks.WithLogger(testcontainers.TestLogger(t))}, opts...)
+	container, err := microcks.Run(ctx, microcks.DefaultImage, opts...)
+	if container != nil {
+		// Registered before failing on err, so that a container failing its initialization is terminated too.
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), terminateTimeout)
+			defer cancel()
+			if err := container.Terminate(ctx); err != nil {
+				t.Errorf("Unable to terminate Microcks container %s: %s", shortID(container), err)
+			}
+		})
+		DumpLogsOnFailure(t, container)
+	}
+	if err != nil {
+		t.Fatalf("Unable to start Microcks: %s", err)
+	}
+	t.Logf("Microcks started in %s", container.StartupReport().Total())
+
+	return container
+}
+
+// testDeadline returns the deadline of the test, if any, as set by go test -timeout.
+func testDeadline(t testing.TB) (time.Time, bool) {
+	if d, ok := t.(interface{ Deadline() (time.Time, bool) }); ok {
+		return d.Deadline()
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microckstest
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	microcks "microcks.io/testcontainers-go"
+	"microcks.io/testcontainers-go/ensemble"
+)
+
+// Suite represents a testify suite running Microcks for its tests: a container, or an ensemble when EnsembleOptions
+// is set, is started by SetupSuite and terminated by TearDownSuite, its logs being dumped to the test log when a
+// test failed. Embed it in a suite and set the options before running it:
+//
+//	type OrdersSuite struct {
+//		microckstest.Suite
+//	}
+//
+//	func TestOrders(t *testing.T) {
+//		suite.Run(t, &OrdersSuite{Suite: microckstest.Suite{
+//			Options: []testcontainers.ContainerCustomizer{microcks.WithMainArtifact("testdata/orders-openapi.yaml")},
+//		}})
+//	}
+//
+// Suites overriding SetupSuite or TearDownSuite have to call the Suite ones, e.g. after computing options.
+type Suite struct {
+	suite.Suite
+
+	// Options represents the options of the Microcks container, as for Run. Ignored when EnsembleOptions is set.
+	Options []testcontainers.ContainerCustomizer
+
+	// EnsembleOptions represents the options of an ensemble started instead of a single container, when set.
+	EnsembleOptions []ensemble.Option
+
+	// Microcks represents the Microcks container, started by SetupSuite, or the one of the ensemble.
+	Microcks *microcks.MicrocksContainer
+
+	// Ensemble represents the ensemble, started by SetupSuite when EnsembleOptions is set.
+	Ensemble *ensemble.MicrocksContainersEnsemble
+}
+
+// SetupSuite starts Microcks, failing the suite with the startup error if any.
+func (s *Suite) SetupSuite() {
+	t := s.T()
+	t.Helper()
+
+	logger := testcontainers.TestLogger(t)
+	if len(s.EnsembleOptions) == 0 {
+		opts := append([]testcontainers.ContainerCustomizer{microcks.WithLogger(logger)}, s.Options...)
+		container, err := microcks.Run(context.Background(), microcks.DefaultImage, opts...)
+		s.Microcks = container
+		if err != nil {
+			// TearDownSuite is not called when SetupSuite fails, so a started container is terminated now.
+			t.Errorf("Unable to start Microcks: %s", err)
+			s.TearDownSuite()
+			t.FailNow()
+		}
+		return
+	}
+
+	opts := append([]ensemble.Option{ensemble.WithLogger(logger)}, s.EnsembleOptions...)
+	ec, err := ensemble.RunContainers(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("Unable to start Microcks ensemble: %s", err)
+	}
+	s.Ensemble = ec
+	s.Microcks = ec.GetMicrocksContainer()
+}
+
+// TearDownSuite dumps the logs of Microcks, or of every ensemble member, when a test failed, then terminates it.
+func (s *Suite) TearDownSuite() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), terminateTimeout)
+	defer cancel()
+
+	switch {
+	case s.Ensemble != nil:
+		if t.Failed() {
+			var logs strings.Builder
+			if _, err := io.Copy(&logs, s.Ensemble.Logs(ctx)); err != nil {
+				t.Logf("Unable to dump logs of Microcks ensemble: %s", err)
+			}
+			t.Logf("Logs of Microcks ensemble:\n%s", logs.String())
+		}
+		if err := s.Ensemble.Terminate(ctx); err != nil {
+			t.Errorf("Unable to terminate Microcks ensemble: %s", err)
+		}
+	case s.Microcks != nil:
+		if t.Failed() {
+			var logs strings.Builder
+			if err := dumpLogs(ctx, s.Microcks, &logs); err != nil {
+				t.Logf("Unable to dump logs of container %s: %s", shortID(s.Microcks), err)
+			} else {
+				t.Logf("Logs of container %s:\n%s", shortID(s.Microcks), logs.String())
+			}
+		}
+		if err := s.Microcks.Terminate(ctx); err != nil {
+			t.Errorf("Unable to terminate Microcks container %s: %s", shortID(s.Microcks), err)
+		}
+	}
+	s.Ensemble, s.Microcks = nil, nil
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+// operationKey represents the key of an operation of a Service.
+type operationKey struct {
+	serviceName    string
+	serviceVersion string
+	operationName  string
+}
+
+// Dispatcher gets the dispatcher and dispatcher rules of an operation of a Service, e.g. URI_PARAMS and "page && size".
+func (container *MicrocksContainer) Dispatcher(ctx context.Context, serviceName, serviceVersion, operationName string) (apiclient.Dispatcher, string, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, operation, err := findOperation(ctx, c, serviceName, serviceVersion, operationName)
+	if err != nil {
+		return "", "", err
+	}
+	return operation.Dispatcher, operation.DispatcherRules, nil
+}
+
+// SetDispatcher temporarily switches the dispatcher and dispatcher rules of an operation of a Service, e.g. from
+// URI_PARAMS to SCRIPT to enable advanced mock behaviors without editing the artifact. It returns a function
+// restoring the previous dispatcher, e.g. to defer or register with t.Cleanup.
+func (container *MicrocksContainer) SetDispatcher(ctx context.Context, serviceName, serviceVersion, operationName string, dispatcher apiclient.Dispatcher, rules string) (func(context.Context) error, error) {
+	key := operationKey{serviceName, serviceVersion, operationName}
+	previous, err := container.updateOperation(ctx, key, func(override *apiclient.OperationOverride) {
+		override.Dispatcher = dispatcher
+		override.DispatcherRules = rules
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		_, err := container.updateOperation(ctx, key, func(override *apiclient.OperationOverride) {
+			override.Dispatcher = previous.Dispatcher
+			override.DispatcherRules = previous.DispatcherRules
+		})
+		return err
+	}, nil
+}
+
+// SetOperationDelay sets the delay, in milliseconds, applied to the mock responses of an operation of a Service, e.g.
+// to simulate a slow dependency and verify the timeout and retry behavior of the application under test. Original
+// delays are restored by ResetOperationDelays.
+func (container *MicrocksContainer) SetOperationDelay(ctx context.Context, serviceName, serviceVersion, operationName string, delayMs int) error {
+	if delayMs < 0 {
+		return fmt.Errorf("error setting operation delay: delay must not be negative, actual %d", delayMs)
+	}
+
+	key := operationKey{serviceName, serviceVersion, operationName}
+	previous, err := container.updateOperation(ctx, key, func(override *apiclient.OperationOverride) {
+		override.DefaultDelay = delayMs
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only the delay before the first change is the original one.
+	container.delaysMutex.Lock()
+	defer container.delaysMutex.Unlock()
+	if container.originalDelays == nil {
+		container.originalDelays = make(map[operationKey]int)
+	}
+	if _, ok := container.originalDelays[key]; !ok {
+		container.originalDelays[key] = previous.DefaultDelay
+	}
+	return nil
+}
+
+// ResetOperationDelays restores the original delays of the operations changed by SetOperationDelay, e.g. between
+// test cases.
+func (container *MicrocksContainer) ResetOperationDelays(ctx context.Context) error {
+	container.delaysMutex.Lock()
+	defer container.delaysMutex.Unlock()
+
+	var errs []error
+	for key, delay := range container.originalDelays {
+		_, err := container.updateOperation(ctx, key, func(override *apiclient.OperationOverride) {
+			override.DefaultDelay = delay
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(container.originalDelays, key)
+	}
+	return errors.Join(errs...)
+}
+
+// Messages lists the request and response examples, or event messages, of an operation of a Service, e.g. to
+// assert which example a mock answered with. See MatchExample.
+func (container *MicrocksContainer) Messages(ctx context.Context, serviceName, serviceVersion, operationName string) ([]apiclient.Message, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	service, _, err := findOperation(ctx, c, serviceName, serviceVersion, operationName)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := c.ListMessages(ctx, service.ID)
+	if err != nil {
+		return nil, err
+	}
+	return messages[operationName], nil
+}
+
+// MatchExample returns the name of the example among messages, as listed by Messages, whose response matches the
+// status code and body of an exchange recorded by a TrafficCapture. JSON bodies are compared regardless of
+// formatting. Microcks does not tell which example it answered with, so examples having the same response cannot
+// be told apart: the first one is returned.
+func MatchExample(messages []apiclient.Message, exchange Exchange) (string, bool) {
+	for _, message := range messages {
+		response := message.Response
+		if response == nil {
+			continue
+		}
+		if response.Status != "" && response.Status != strconv.Itoa(exchange.StatusCode) {
+			continue
+		}
+		if sameContent(response.Content, exchange.ResponseBody) {
+			return message.Name(), true
+		}
+	}
+	return "", false
+}
+
+// sameContent tells if an example content and a body are the same, regardless of formatting when both are JSON.
+func sameContent(content string, body []byte) bool {
+	var expected, actual any
+	if json.Unmarshal([]byte(content), &expected) == nil && json.Unmarshal(body, &actual) == nil {
+		return reflect.DeepEqual(expected, actual)
+	}
+	return strings.TrimSpace(content) == strings.TrimSpace(string(body))
+}
+
+// OperationInvocations gets the number of requests received by each REST operation of given Service, e.g.
+// {"GET /pastries/{name}": 2, "DELETE /pastries/{name}": 0}, so that a test can assert which operations were called.
+// Every operation of the Service is listed, requests not matching any being ignored. Requests are read from the
+// container logs, as by ReceivedRequests, so counts are not relative to ResetInvocationStats. Use
+// TrafficCapture.OperationStatusCodes to also get the response status codes.
+func (container *MicrocksContainer) OperationInvocations(ctx context.Context, serviceName, serviceVersion string) (map[string]int, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := c.FindService(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := container.ReceivedRequests(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	invocations := make(map[string]int, len(service.Operations))
+	for _, operation := range service.Operations {
+		invocations[operation.Name] = 0
+	}
+	for _, request := range requests {
+		if name, ok := matchOperation(service, request.Method, request.URI); ok {
+			invocations[name]++
+		}
+	}
+	return invocations, nil
+}
+
+// matchOperation returns the name of the REST operation of service serving a request of given method and mock URI,
+// e.g. "GET /pastries/{name}" for GET "/rest/API Pastries/0.0.1/pastries/Millefeuille?size=S". When several
+// operations match, the one having the most literal path segments wins.
+func matchOperation(service *apiclient.Service, method, uri string) (string, bool) {
+	path, _, _ := strings.Cut(uri, "?")
+	prefix := "/" + service.Name + "/" + service.Version
+	_, path, found := strings.Cut(path, prefix)
+	if !found {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	best, bestLiterals := "", -1
+	for _, operation := range service.Operations {
+		operationMethod, template, ok := strings.Cut(operation.Name, " ")
+		if !ok || !strings.EqualFold(operationMethod, method) {
+			continue
+		}
+		templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		for i, segment := range templateSegments {
+			if (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) || strings.HasPrefix(segment, ":") {
+				continue
+			}
+			if segment != segments[i] {
+				literals = -1
+				break
+			}
+			literals++
+		}
+		if literals > bestLiterals {
+			best, bestLiterals = operation.Name, literals
+		}
+	}
+	return best, bestLiterals >= 0
+}
+
+// updateOperation applies update to the current mocking settings of an operation and returns the previous ones.
+func (container *MicrocksContainer) updateOperation(ctx context.Context, key operationKey, update func(*apiclient.OperationOverride)) (apiclient.OperationOverride, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return apiclient.OperationOverride{}, err
+	}
+
+	service, operation, err := findOperation(ctx, c, key.serviceName, key.serviceVersion, key.operationName)
+	if err != nil {
+		return apiclient.OperationOverride{}, err
+	}
+
+	previous := operation.Override()
+	override := previous
+	update(&override)
+	if err := c.UpdateOperation(ctx, service.ID, key.operationName, override); err != nil {
+		return apiclient.OperationOverride{}, err
+	}
+	return previous, nil
+}
+
+func findOperation(ctx context.Context, c *apiclient.Client, serviceName, serviceVersion, operationName string) (*apiclient.Service, *apiclient.Operation, error) {
+	service, err := c.FindService(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	operation := service.Operation(operationName)
+	if operation == nil {
+		return nil, nil, fmt.Errorf("unable to find operation %s of service %s:%s", operationName, serviceName, serviceVersion)
+	}
+	return service, operation, nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// progressInterval represents the minimum interval between two upload progress reports.
+const progressInterval = 250 * time.Millisecond
+
+// ProgressOperation represents a long-running operation reporting its progress.
+type ProgressOperation string
+
+const (
+	// ProgressArtifactImport is reported while an artifact is uploaded or a remote artifact imported.
+	ProgressArtifactImport ProgressOperation = "ArtifactImport"
+	// ProgressSnapshotUpload is reported while a snapshot is uploaded.
+	ProgressSnapshotUpload ProgressOperation = "SnapshotUpload"
+	// ProgressTestPolling is reported each time TestEndpoint polls the result of a test still in progress.
+	ProgressTestPolling ProgressOperation = "TestPolling"
+)
+
+// Progress represents the progress of a long-running operation.
+type Progress struct {
+	// Operation represents the running operation.
+	Operation ProgressOperation
+
+	// Subject represents what the operation is about: the artifact path or URL, the snapshot file name or the
+	// tested Service as "name:version".
+	Subject string
+
+	// SentBytes represents the number of bytes uploaded so far, for uploads.
+	SentBytes int64
+
+	// TotalBytes represents the number of bytes to upload, for uploads, -1 when unknown.
+	TotalBytes int64
+
+	// Elapsed represents the time elapsed since the operation started.
+	Elapsed time.Duration
+
+	// Done tells if the operation is finished.
+	Done bool
+}
+
+// WithProgress registers a callback receiving the progress of artifact imports, snapshot uploads and test result
+// polling, e.g. to report status to the test log rather than appearing hung. The callback is called synchronously,
+// so it must not block.
+func WithProgress(callback func(Progress)) Option {
+	return func(o *options) error {
+		o.progress = callback
+		return nil
+	}
+}
+
+// progressTracker reports the progress of an operation to the container callback.
+type progressTracker struct {
+	callback func(Progress)
+	progress Progress
+	start    time.Time
+	last     time.Time
+	mutex    sync.Mutex
+}
+
+// trackProgress starts tracking an operation. It returns nil when no callback is registered.
+func (container *MicrocksContainer) trackProgress(operation ProgressOperation, subject string) *progressTracker {
+	if container.progress == nil {
+		return nil
+	}
+	return &progressTracker{
+		callback: container.progress,
+		progress: Progress{Operation: operation, Subject: subject},
+		start:    time.Now(),
+	}
+}
+
+// report reports the current progress, if tracked.
+func (tracker *progressTracker) report() {
+	if tracker == nil {
+		return
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.last = time.Now()
+	tracker.progress.Elapsed = tracker.last.Sub(tracker.start)
+	tracker.callback(tracker.progress)
+}
+
+// done reports the operation is finished, if tracked.
+func (tracker *progressTracker) done() {
+	if tracker == nil {
+		return
+	}
+	tracker.mutex.Lock()
+	tracker.progress.Done = true
+	tracker.mutex.Unlock()
+	tracker.report()
+}
+
+// upload returns a reader of r, having given size (-1 when unknown), reporting the upload progress, or r itself
+// when not tracked. The reader keeps the size and seeking abilities of r, so that uploads are still sent with
+// their content length and replayed on retry.
+func (tracker *progressTracker) upload(r io.Reader, size int64) io.Reader {
+	if tracker == nil {
+		return r
+	}
+	tracker.progress.TotalBytes = size
+	tracker.report()
+
+	reader := &progressReader{reader: r, tracker: tracker}
+	if seeker, ok := r.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			reader.seeker, reader.start = seeker, offset
+		}
+	}
+	return reader
+}
+
+// fileSize returns the size of file, -1 when unknown.
+func fileSize(file *os.File) int64 {
+	info, err := file.Stat()
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
+
+// progressReader represents a reader reporting the bytes read, at most every progressInterval and once fully read.
+// seeker is the reader when it can seek, start being its offset when tracking started.
+type progressReader struct {
+	reader  io.Reader
+	tracker *progressTracker
+	seeker  io.Seeker
+	start   int64
+}
+
+// Len returns the number of bytes remaining to read, or -1 when unknown.
+func (r *progressReader) Len() int {
+	r.tracker.mutex.Lock()
+	defer r.tracker.mutex.Unlock()
+	if r.tracker.progress.TotalBytes < 0 {
+		return -1
+	}
+	return int(r.tracker.progress.TotalBytes - r.tracker.progress.SentBytes)
+}
+
+// Seek seeks the reader, e.g. to replay an upload, the bytes sent being counted from the new offset. It fails when
+// the reader cannot seek.
+func (r *progressReader) Seek(offset int64, whence int) (int64, error) {
+	if r.seeker == nil {
+		return 0, fmt.Errorf("error seeking upload: reader cannot seek")
+	}
+	position, err := r.seeker.Seek(offset, whence)
+	if err != nil {
+		return position, err
+	}
+
+	r.tracker.mutex.Lock()
+	r.tracker.progress.SentBytes = position - r.start
+	r.tracker.mutex.Unlock()
+	return position, nil
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	r.tracker.mutex.Lock()
+	r.tracker.progress.SentBytes += int64(n)
+	due := (n > 0 && time.Since(r.tracker.last) >= progressInterval) || err == io.EOF
+	r.tracker.mutex.Unlock()
+	if due {
+		r.tracker.report()
+	}
+	return n, err
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+// Connect creates a MicrocksContainer bound to an already deployed Microcks instance reachable at given HTTP
+// endpoint, e.g. a shared staging instance, so that the same test code runs against local containers and remote
+// instances. Options are applied as for RunContainer, container customizers are ignored: use WithServiceAccount
+// for authentication and WithRemoteGrpcEndpoint to enable GrpcMockEndpoint.
+// Container features (logs, ports, exec...) are not available and return an error, Terminate does nothing. As for
+// RunContainer, the container is returned along with an error creating secrets or importing artifacts.
+func Connect(ctx context.Context, httpEndpoint string, opts ...testcontainers.ContainerCustomizer) (*MicrocksContainer, error) {
+	endpoint, err := url.Parse(httpEndpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("error connecting to Microcks: invalid endpoint %q", httpEndpoint)
+	}
+
+	settings := options{}
+	req := testcontainers.GenericContainerRequest{}
+	stopRecording := settings.record(&req)
+	defer stopRecording()
+	for _, opt := range opts {
+		// Container customizers are ignored, as they may have side effects, e.g. creating a network.
+		if !isRecordedOption(opt) {
+			continue
+		}
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+
+	microcksContainer := &MicrocksContainer{
+		Container:            remoteContainer{},
+		serviceAccount:       settings.serviceAccount,
+		credentials:          settings.credentials,
+		tls:                  settings.tls,
+		grpcTLS:              settings.grpcTLS,
+		remoteHttpEndpoint:   strings.TrimSuffix(httpEndpoint, "/"),
+		remoteGrpcEndpoint:   settings.remoteGrpcEndpoint,
+		logger:               settings.logger,
+		slowRequestThreshold: settings.slowRequestThreshold,
+		progress:             settings.progress,
+		apiTracing:           settings.apiTracing,
+		retryPolicy:          settings.retryPolicy,
+		rateLimiter:          settings.rateLimiter,
+		httpClient:           settings.httpClient,
+		roundTrippers:        settings.roundTrippers,
+		serviceCache:         apiclient.NewServiceCache(),
+		statsSince:           time.Now(),
+	}
+	if err := microcksContainer.initialize(ctx, &settings); err != nil {
+		return microcksContainer, err
+	}
+
+	return microcksContainer, nil
+}
+
+// WithRemoteGrpcEndpoint provides the gRPC endpoint of a remote Microcks instance, e.g. "grpc://microcks-grpc.example.com:443".
+func WithRemoteGrpcEndpoint(grpcEndpoint string) Option {
+	return func(o *options) error {
+		o.remoteGrpcEndpoint = grpcEndpoint
+		return nil
+	}
+}
+
+// IsRemote tells if the container is bound to a remote Microcks instance, see Connect.
+func (container *MicrocksContainer) IsRemote() bool {
+	return container.remoteHttpEndpoint != ""
+}
+
+// Terminate terminates the Microcks container and closes its events channel. It does nothing else for a remote
+// Microcks instance.
+func (container *MicrocksContainer) Terminate(ctx context.Context) error {
+	container.closeEvents()
+	if container.IsRemote() {
+		return nil
+	}
+	return container.Container.Terminate(ctx)
+}
+
+// errRemoteContainer is returned by container features of a remote Microcks instance.
+var errRemoteContainer = errors.New("container features are unavailable on a remote Microcks instance")
+
+// remoteContainer stands for the container of a remote Microcks instance, see Connect.
+type remoteContainer struct{}
+
+func (remoteContainer) GetContainerID() string { return "" }
+
+func (remoteContainer) Endpoint(context.Context, string) (string, error) {
+	return "", errRemoteContainer
+}
+
+func (remoteContainer) PortEndpoint(context.Context, nat.Port, string) (string, error) {
+	return "", errRemoteContainer
+}
+
+func (remoteContainer) Host(context.Context) (string, error) { return "", errRemoteContainer }
+
+func (remoteContainer) Inspect(context.Context) (*types.ContainerJSON, error) {
+	return nil, errRemoteContainer
+}
+
+func (remoteContainer) MappedPort(context.Context, nat.Port) (nat.Port, error) {
+	return "", errRemoteContainer
+}
+
+func (remoteContainer) Ports(context.Context) (nat.PortMap, error) { return nil, errRemoteContainer }
+
+func (remoteContainer) SessionID() string { return "" }
+
+func (remoteContainer) IsRunning() bool { return false }
+
+func (remoteContainer) Start(context.Context) error { return errRemoteContainer }
+
+func (remoteContainer) Stop(context.Context, *time.Duration) error { return errRemoteContainer }
+
+func (remoteContainer) Terminate(context.Context) error { return nil }
+
+func (remoteContainer) Logs(context.Context) (io.ReadCloser, error) { return nil, errRemoteContainer }
+
+func (remoteContainer) FollowOutput(testcontainers.LogConsumer) {}
+
+func (remoteContainer) StartLogProducer(context.Context, ...testcontainers.LogProductionOption) error {
+	return errRemoteContainer
+}
+
+func (remoteContainer) StopLogProducer() error { return nil }
+
+func (remoteContainer) Name(context.Context) (string, error) { return "", errRemoteContainer }
+
+func (remoteContainer) State(context.Context) (*types.ContainerState, error) {
+	return nil, errRemoteContainer
+}
+
+func (remoteContainer) Networks(context.Context) ([]string, error) { return nil, errRemoteContainer }
+
+func (remoteContainer) NetworkAliases(context.Context) (map[string][]string, error) {
+	return nil, errRemoteContainer
+}
+
+func (remoteContainer) Exec(context.Context, []string, ...tcexec.ProcessOption) (int, io.Reader, error) {
+	return 0, nil, errRemoteContainer
+}
+
+func (remoteContainer) ContainerIP(context.Context) (string, error) { return "", errRemoteContainer }
+
+func (remoteContainer) ContainerIPs(context.Context) ([]string, error) {
+	return nil, errRemoteContainer
+}
+
+func (remoteContainer) CopyToContainer(context.Context, []byte, string, int64) error {
+	return errRemoteContainer
+}
+
+func (remoteContainer) CopyDirToContainer(context.Context, string, string, int64) error {
+	return errRemoteContainer
+}
+
+func (remoteContainer) CopyFileToContainer(context.Context, string, string, int64) error {
+	return errRemoteContainer
+}
+
+func (remoteContainer) CopyFileFromContainer(context.Context, string) (io.ReadCloser, error) {
+	return nil, errRemoteContainer
+}
+
+func (remoteContainer) GetLogProductionErrorChannel() <-chan error { return nil }
@@ -0,0 +1,135 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// StartupPhase represents a phase of the Microcks startup and how long it took.
+type StartupPhase struct {
+	// Name represents the phase name, e.g. "image pull" or "artifact apipastries-openapi.yaml".
+	Name string
+
+	// Duration represents how long the phase took.
+	Duration time.Duration
+}
+
+// StartupReport represents the timing breakdown of the Microcks startup: image pull, container creation, start
+// and readiness, then each secret creation and artifact import, in order.
+type StartupReport struct {
+	// Phases represents the startup phases, in order.
+	Phases []StartupPhase
+}
+
+// Total returns the total startup duration.
+func (report StartupReport) Total() time.Duration {
+	var total time.Duration
+	for _, phase := range report.Phases {
+		total += phase.Duration
+	}
+	return total
+}
+
+// String returns a human readable breakdown, one phase per line.
+func (report StartupReport) String() string {
+	var b strings.Builder
+	for _, phase := range report.Phases {
+		fmt.Fprintf(&b, "%-60s %10s\n", phase.Name, phase.Duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(&b, "%-60s %10s\n", "total", report.Total().Round(time.Millisecond))
+	return b.String()
+}
+
+// StartupReport returns the timing breakdown of the container startup. For a remote Microcks instance, see Connect,
+// it only holds secret creations and artifact imports.
+func (container *MicrocksContainer) StartupReport() StartupReport {
+	if container.startupClock == nil {
+		return StartupReport{}
+	}
+	return container.startupClock.snapshot()
+}
+
+// startupClock records the startup phases, each phase lasting since the end of the previous one.
+type startupClock struct {
+	mutex  sync.Mutex
+	last   time.Time
+	report StartupReport
+}
+
+func newStartupClock() *startupClock {
+	return &startupClock{last: time.Now()}
+}
+
+// lap ends the current phase under given name and starts the next one.
+func (clock *startupClock) lap(name string) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	now := time.Now()
+	clock.report.Phases = append(clock.report.Phases, StartupPhase{Name: name, Duration: now.Sub(clock.last)})
+	clock.last = now
+}
+
+// restart starts the next phase now, e.g. to ignore the time spent between phases.
+func (clock *startupClock) restart() {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.last = time.Now()
+}
+
+func (clock *startupClock) snapshot() StartupReport {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return StartupReport{Phases: append([]StartupPhase(nil), clock.report.Phases...)}
+}
+
+// hooks returns the lifecycle hooks recording container phases. Image is pulled, if needed, before the
+// container is created.
+func (clock *startupClock) hooks() testcontainers.ContainerLifecycleHooks {
+	lap := func(name string) testcontainers.ContainerHook {
+		return func(context.Context, testcontainers.Container) error {
+			clock.lap(name)
+			return nil
+		}
+	}
+	return testcontainers.ContainerLifecycleHooks{
+		PreCreates: []testcontainers.ContainerRequestHook{
+			func(context.Context, testcontainers.ContainerRequest) error {
+				clock.lap("image pull")
+				return nil
+			},
+		},
+		PostCreates: []testcontainers.ContainerHook{lap("container creation")},
+		PostStarts:  []testcontainers.ContainerHook{lap("container start")},
+		PostReadies: []testcontainers.ContainerHook{lap("container ready")},
+	}
+}
+
+// lap records the end of a startup phase of the container.
+func (container *MicrocksContainer) lap(name string) {
+	if container.startupClock != nil {
+		container.startupClock.lap(name)
+	}
+}
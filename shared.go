@@ -0,0 +1,90 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// sharedContainer represents a Microcks container shared by the tests of a package, started once and terminated
+// when its last reference is released.
+type sharedContainer struct {
+	once      sync.Once
+	container *MicrocksContainer
+	err       error
+	refs      int
+}
+
+var (
+	sharedMutex   sync.Mutex
+	currentShared *sharedContainer
+)
+
+// Shared returns a Microcks container shared by all the callers of the package, e.g. tests of different files
+// running with t.Parallel(). The container is started with the default image and options of the first call,
+// later calls waiting for it to be ready, and is terminated once every returned release function has been called.
+// Hold a reference from TestMain to keep the container across sequential tests:
+//
+//	microcksContainer, release, err := microcks.Shared(ctx, microcks.WithMainArtifact("testdata/apipastries-openapi.yaml"))
+//	require.NoError(t, err)
+//	t.Cleanup(func() { _ = release(ctx) })
+func Shared(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*MicrocksContainer, func(context.Context) error, error) {
+	sharedMutex.Lock()
+	if currentShared == nil {
+		currentShared = &sharedContainer{}
+	}
+	shared := currentShared
+	shared.refs++
+	sharedMutex.Unlock()
+
+	shared.once.Do(func() {
+		shared.container, shared.err = Run(ctx, DefaultImage, opts...)
+	})
+
+	var releaseOnce sync.Once
+	release := func(ctx context.Context) (err error) {
+		releaseOnce.Do(func() {
+			err = shared.release(ctx)
+		})
+		return err
+	}
+	if shared.err != nil {
+		_ = release(ctx)
+		return nil, nil, shared.err
+	}
+
+	return shared.container, release, nil
+}
+
+// release drops a reference to the shared container, terminating it when it was the last one. Next call to
+// Shared then starts a new container.
+func (shared *sharedContainer) release(ctx context.Context) error {
+	sharedMutex.Lock()
+	shared.refs--
+	last := shared.refs == 0
+	if last && currentShared == shared {
+		currentShared = nil
+	}
+	sharedMutex.Unlock()
+
+	if !last || shared.container == nil {
+		return nil
+	}
+	return shared.container.Terminate(ctx)
+}
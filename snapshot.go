@@ -0,0 +1,176 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+// ExportSnapshot writes a snapshot of the Microcks repository, holding every service with its operations and
+// mock responses, to w. It can be imported later using ImportSnapshot or RestoreSnapshot.
+func (container *MicrocksContainer) ExportSnapshot(ctx context.Context, w io.Writer) error {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	services, err := c.ListServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	serviceIDs := make([]string, 0, len(services))
+	for _, s := range services {
+		serviceIDs = append(serviceIDs, s.ID)
+	}
+	return c.ExportSnapshot(ctx, w, serviceIDs...)
+}
+
+// RestoreSnapshot replaces the content of the Microcks repository with given snapshot, as written by
+// ExportSnapshot: existing services are deleted before the snapshot is imported.
+func (container *MicrocksContainer) RestoreSnapshot(ctx context.Context, snapshot io.Reader) error {
+	if err := container.DeleteServices(ctx); err != nil {
+		return err
+	}
+
+	statusCode, err := container.importSnapshot(ctx, "snapshot.json", snapshot, -1)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusCreated {
+		return apiclient.NewStatusError("restore snapshot", statusCode, http.StatusCreated, ErrArtifactRejected)
+	}
+
+	return nil
+}
+
+// ListServices lists the Services imported in the Microcks repository, e.g. to drive table-driven assertions.
+// Use GetService to get their operations.
+func (container *MicrocksContainer) ListServices(ctx context.Context) ([]apiclient.Service, error) {
+	return container.listServices(ctx)
+}
+
+// GetService gets the Service having given identifier, with its operations and their dispatching rules.
+func (container *MicrocksContainer) GetService(ctx context.Context, id string) (*apiclient.Service, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetService(ctx, id)
+}
+
+// ListResources lists the contract and schema Resources held for the Service having given identifier.
+func (container *MicrocksContainer) ListResources(ctx context.Context, serviceID string) ([]apiclient.Resource, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ListResources(ctx, serviceID)
+}
+
+// ExportResources writes the contract and schema Resources Microcks holds for the Service having given name and
+// version to dir, e.g. to verify what a snapshot or remote import actually contained. Resources are written under
+// their path when they are referenced by the main artifact, under their name otherwise. It returns the written files.
+func (container *MicrocksContainer) ExportResources(ctx context.Context, serviceName, serviceVersion, dir string) ([]string, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := c.FindService(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := c.ListResources(ctx, service.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		name := filepath.FromSlash(path.Clean(strings.TrimPrefix(resource.Path, "./")))
+		if resource.Path == "" {
+			name = filepath.Base(resource.Name)
+		}
+		if !filepath.IsLocal(name) {
+			return files, fmt.Errorf("error exporting resource %s: path %q is outside of the export directory", resource.Name, resource.Path)
+		}
+
+		fileName := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+			return files, fmt.Errorf("error creating resource directory: %w", err)
+		}
+		if err := os.WriteFile(fileName, []byte(resource.Content), 0o644); err != nil {
+			return files, fmt.Errorf("error writing resource %s: %w", resource.Name, err)
+		}
+		files = append(files, fileName)
+	}
+
+	return files, nil
+}
+
+// DeleteServices deletes every service of the Microcks repository.
+func (container *MicrocksContainer) DeleteServices(ctx context.Context) error {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	services, err := c.ListServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range services {
+		if err := c.DeleteService(ctx, s.ID); err != nil {
+			return fmt.Errorf("error deleting service %s:%s: %w", s.Name, s.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// importSnapshot uploads snapshot content, named fileName and having given size (-1 when unknown), to the Microcks
+// import API.
+func (container *MicrocksContainer) importSnapshot(ctx context.Context, fileName string, snapshot io.Reader, size int64) (int, error) {
+	c, err := container.APIClient(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tracker := container.trackProgress(ProgressSnapshotUpload, fileName)
+	statusCode, err := createdStatusCode(c.ImportSnapshot(ctx, fileName, tracker.upload(snapshot, size)))
+	if err != nil {
+		return statusCode, err
+	}
+	tracker.done()
+
+	if statusCode == http.StatusCreated {
+		container.emit(Event{Type: EventArtifactImported, Subject: fileName})
+	}
+	return statusCode, nil
+}
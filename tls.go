@@ -0,0 +1,328 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	apiclient "microcks.io/testcontainers-go/client"
+)
+
+const (
+	tlsCertificatePath  = "/deployments/tls/tls.crt"
+	tlsPrivateKeyPath   = "/deployments/tls/tls.key"
+	grpcCertificatePath = "/deployments/grpc/tls.crt"
+	grpcPrivateKeyPath  = "/deployments/grpc/tls.key"
+)
+
+// tlsSettings represents the PEM encoded certificate, key and CA used to serve Microcks over HTTPS.
+type tlsSettings struct {
+	certificate []byte
+	privateKey  []byte
+	ca          []byte
+}
+
+// WithTLS serves Microcks endpoints over HTTPS using given PEM encoded certificate and private key files.
+// caFile is the PEM encoded CA that signed the certificate; when empty, the certificate itself is trusted.
+func WithTLS(certFile, keyFile, caFile string) Option {
+	return func(o *options) error {
+		settings, err := readTLSSettings(certFile, keyFile, caFile)
+		if err != nil {
+			return err
+		}
+
+		o.tls = settings
+		return nil
+	}
+}
+
+// WithSelfSignedTLS serves Microcks endpoints over HTTPS using an auto-generated self-signed certificate.
+// The certificate is valid for localhost, the Docker daemon host, the Microcks network alias and the given
+// additional hosts.
+func WithSelfSignedTLS(hosts ...string) Option {
+	return func(o *options) error {
+		certificate, privateKey, err := generateSelfSignedCertificate(selfSignedHosts(hosts))
+		if err != nil {
+			return fmt.Errorf("error generating self-signed certificate: %w", err)
+		}
+
+		o.tls = &tlsSettings{certificate: certificate, privateKey: privateKey, ca: certificate}
+		return nil
+	}
+}
+
+// WithGrpcTLS serves the Microcks gRPC mock endpoint over TLS using given PEM encoded certificate and
+// private key files. caFile is the PEM encoded CA that signed the certificate; when empty, the certificate
+// itself is trusted.
+func WithGrpcTLS(certFile, keyFile, caFile string) Option {
+	return func(o *options) error {
+		settings, err := readTLSSettings(certFile, keyFile, caFile)
+		if err != nil {
+			return err
+		}
+
+		o.grpcTLS = settings
+		return nil
+	}
+}
+
+// WithSelfSignedGrpcTLS serves the Microcks gRPC mock endpoint over TLS using an auto-generated self-signed
+// certificate. The certificate is valid for localhost, the Docker daemon host, the Microcks network alias and
+// the given additional hosts.
+func WithSelfSignedGrpcTLS(hosts ...string) Option {
+	return func(o *options) error {
+		certificate, privateKey, err := generateSelfSignedCertificate(selfSignedHosts(hosts))
+		if err != nil {
+			return fmt.Errorf("error generating self-signed certificate: %w", err)
+		}
+
+		o.grpcTLS = &tlsSettings{certificate: certificate, privateKey: privateKey, ca: certificate}
+		return nil
+	}
+}
+
+// GrpcTLSConfig returns a TLS configuration trusting the CA of the gRPC mock endpoint, to be used by gRPC
+// clients, or nil when gRPC TLS is not enabled. Microcks only supports server-side TLS: client certificates are
+// not verified by the mock endpoint.
+func (container *MicrocksContainer) GrpcTLSConfig() *tls.Config {
+	if container.grpcTLS == nil {
+		return nil
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(container.grpcTLS.ca)
+	return &tls.Config{RootCAs: pool}
+}
+
+// CACertificate returns the PEM encoded CA the application under test has to trust to call Microcks
+// over HTTPS, or nil when TLS is not enabled.
+func (container *MicrocksContainer) CACertificate() []byte {
+	if container.tls == nil {
+		return nil
+	}
+
+	return container.tls.ca
+}
+
+// CertPool returns a certificate pool trusting the Microcks CA, or nil when TLS is not enabled.
+func (container *MicrocksContainer) CertPool() *x509.CertPool {
+	if container.tls == nil {
+		return nil
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(container.tls.ca)
+	return pool
+}
+
+// apiHTTPClient returns the HTTP client used to call Microcks, based on the one set by WithHTTPClient if any. It
+// sends static credentials when set, traces calls when API tracing is enabled, limits their rate when a rate
+// limiter is set and retries them when a retry policy is set.
+func (container *MicrocksContainer) apiHTTPClient() *http.Client {
+	return container.newHTTPClient(container.credentials)
+}
+
+// newHTTPClient returns the HTTP client of apiHTTPClient, sending given static credentials. Service account token
+// requests use it without credentials, as these are meant for Microcks only.
+func (container *MicrocksContainer) newHTTPClient(credentials staticCredentials) *http.Client {
+	transport := container.transport()
+	if credentials != (staticCredentials{}) {
+		transport = &credentialsTransport{next: defaultTransport(transport), credentials: credentials}
+	}
+	if mode := container.apiTracingMode(); mode != apiTracingOff {
+		transport = &tracingTransport{next: defaultTransport(transport), bodies: mode == apiTracingBodies, logf: container.logf}
+	}
+	if container.rateLimiter != nil {
+		transport = apiclient.NewRateLimitTransport(transport, container.rateLimiter)
+	}
+	if container.retryPolicy != nil {
+		transport = apiclient.NewRetryTransport(transport, container.retryPolicy)
+	}
+	if container.httpClient == nil && transport == nil {
+		return http.DefaultClient
+	}
+
+	httpClient := &http.Client{}
+	if container.httpClient != nil {
+		*httpClient = *container.httpClient
+	}
+	httpClient.Transport = transport
+	return httpClient
+}
+
+// transport returns the transport to Microcks, or nil for the default one: the transport of the client set by
+// WithHTTPClient if any, or one trusting the Microcks CA when TLS is enabled, wrapped by WithRoundTripper ones.
+func (container *MicrocksContainer) transport() http.RoundTripper {
+	var transport http.RoundTripper
+	switch {
+	case container.httpClient != nil && container.httpClient.Transport != nil:
+		transport = container.httpClient.Transport
+	case container.tls != nil:
+		tlsTransport := http.DefaultTransport.(*http.Transport).Clone()
+		tlsTransport.TLSClientConfig = &tls.Config{RootCAs: container.CertPool()}
+		transport = tlsTransport
+	}
+	for _, wrap := range container.roundTrippers {
+		transport = wrap(defaultTransport(transport))
+	}
+	return transport
+}
+
+// defaultTransport returns transport, or http.DefaultTransport when nil.
+func defaultTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		return http.DefaultTransport
+	}
+	return transport
+}
+
+// scheme returns the scheme of Microcks HTTP endpoints.
+func (container *MicrocksContainer) scheme() string {
+	if container.tls == nil {
+		return "http"
+	}
+
+	return "https"
+}
+
+// customize mounts certificate and private key in the container request and enables HTTPS.
+func (settings *tlsSettings) customize(req *testcontainers.GenericContainerRequest) {
+	settings.mount(req, tlsCertificatePath, tlsPrivateKeyPath)
+
+	req.Env["SERVER_SSL_ENABLED"] = "true"
+	req.Env["SERVER_SSL_CERTIFICATE"] = "file:" + tlsCertificatePath
+	req.Env["SERVER_SSL_CERTIFICATE_PRIVATE_KEY"] = "file:" + tlsPrivateKeyPath
+}
+
+// customizeGrpc mounts certificate and private key in the container request and enables TLS on gRPC server.
+func (settings *tlsSettings) customizeGrpc(req *testcontainers.GenericContainerRequest) {
+	settings.mount(req, grpcCertificatePath, grpcPrivateKeyPath)
+
+	req.Env["GRPC_SERVER_CERT"] = grpcCertificatePath
+	req.Env["GRPC_SERVER_PRIVATE_KEY"] = grpcPrivateKeyPath
+}
+
+// mount copies certificate and private key in the container. Both are world readable, as copied files are owned by
+// root while Microcks runs as a non-root user.
+func (settings *tlsSettings) mount(req *testcontainers.GenericContainerRequest, certificatePath, privateKeyPath string) {
+	req.Files = append(req.Files,
+		testcontainers.ContainerFile{
+			Reader:            bytes.NewReader(settings.certificate),
+			ContainerFilePath: certificatePath,
+			FileMode:          0o644,
+		},
+		testcontainers.ContainerFile{
+			Reader:            bytes.NewReader(settings.privateKey),
+			ContainerFilePath: privateKeyPath,
+			FileMode:          0o644,
+		},
+	)
+
+	if req.Env == nil {
+		req.Env = make(map[string]string)
+	}
+}
+
+func readTLSSettings(certFile, keyFile, caFile string) (*tlsSettings, error) {
+	certificate, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TLS certificate: %w", err)
+	}
+	privateKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TLS private key: %w", err)
+	}
+	ca := certificate
+	if caFile != "" {
+		if ca, err = os.ReadFile(caFile); err != nil {
+			return nil, fmt.Errorf("error reading TLS CA: %w", err)
+		}
+	}
+
+	return &tlsSettings{certificate: certificate, privateKey: privateKey, ca: ca}, nil
+}
+
+// selfSignedHosts returns the hosts a self-signed certificate is valid for. The Docker daemon host is
+// included so that endpoints remain verifiable when containers run on a remote daemon or Testcontainers Cloud.
+func selfSignedHosts(hosts []string) []string {
+	result := []string{"localhost", "127.0.0.1", DefaultNetworkAlias}
+	if provider, err := testcontainers.NewDockerProvider(); err == nil {
+		defer provider.Close()
+		if daemonHost, err := provider.DaemonHost(context.Background()); err == nil && !slices.Contains(result, daemonHost) {
+			result = append(result, daemonHost)
+		}
+	}
+	return append(result, hosts...)
+}
+
+func generateSelfSignedCertificate(hosts []string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{Organization: []string{"Microcks Testcontainers"}, CommonName: DefaultNetworkAlias},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+	return certificate, privateKey, nil
+}
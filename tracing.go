@@ -0,0 +1,144 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// APITracingEnv represents the environment variable enabling API tracing without changing test code: "true" logs
+// calls, "bodies" logs calls with their bodies. See WithAPITracing.
+const APITracingEnv = "MICROCKS_API_TRACING"
+
+// CorrelationIDHeader represents the header carrying the correlation identifier of traced API calls.
+const CorrelationIDHeader = "X-Correlation-Id"
+
+// tracedBodyLimit represents the maximum number of body bytes logged by API tracing.
+const tracedBodyLimit = 1024
+
+// apiTracing represents the API tracing mode.
+type apiTracing int
+
+const (
+	apiTracingOff apiTracing = iota
+	apiTracingOn
+	apiTracingBodies
+)
+
+// WithAPITracing logs every call made by the module to the Microcks API, with method, path, status, duration and a
+// correlation identifier also sent in the X-Correlation-Id header, e.g. to debug interactions. Bodies are only logged,
+// truncated, when includeBodies is set, as they may hold sensitive values. See APITracingEnv to enable it from the
+// environment.
+func WithAPITracing(includeBodies bool) Option {
+	return func(o *options) error {
+		o.apiTracing = apiTracingOn
+		if includeBodies {
+			o.apiTracing = apiTracingBodies
+		}
+		return nil
+	}
+}
+
+// apiTracingMode returns the API tracing mode, set by WithAPITracing or APITracingEnv.
+func (container *MicrocksContainer) apiTracingMode() apiTracing {
+	mode := container.apiTracing
+	switch strings.ToLower(os.Getenv(APITracingEnv)) {
+	case "bodies":
+		mode = max(mode, apiTracingBodies)
+	case "true", "1":
+		mode = max(mode, apiTracingOn)
+	}
+	return mode
+}
+
+// tracingTransport represents a transport logging the calls it makes.
+type tracingTransport struct {
+	next   http.RoundTripper
+	bodies bool
+	logf   func(format string, args ...any)
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	correlationID := newCorrelationID()
+	req = req.Clone(req.Context())
+	req.Header.Set(CorrelationIDHeader, correlationID)
+	if t.bodies {
+		t.logf("[%s] Microcks API request %s %s: %s", correlationID, req.Method, req.URL.Path, requestBody(req))
+	}
+
+	start := time.Now()
+	response, err := t.next.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.logf("[%s] Microcks API call %s %s failed after %s: %s", correlationID, req.Method, req.URL.Path, duration, err)
+		return nil, err
+	}
+
+	t.logf("[%s] Microcks API call %s %s: %d in %s", correlationID, req.Method, req.URL.Path, response.StatusCode, duration)
+	if t.bodies {
+		var body string
+		body, response.Body = peekBody(response.Body)
+		t.logf("[%s] Microcks API response %s %s: %s", correlationID, req.Method, req.URL.Path, body)
+	}
+	return response, nil
+}
+
+// requestBody returns the beginning of the request body, read from a copy of it.
+func requestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "<empty>"
+	}
+	if req.GetBody == nil {
+		return "<streamed>"
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "<unreadable>"
+	}
+	defer body.Close()
+
+	text, _ := peekBody(body)
+	return text
+}
+
+// peekBody returns the beginning of body and a reader of the whole body.
+func peekBody(body io.ReadCloser) (string, io.ReadCloser) {
+	head := make([]byte, tracedBodyLimit+1)
+	n, _ := io.ReadFull(body, head)
+	head = head[:n]
+
+	text := string(head)
+	if n > tracedBodyLimit {
+		text = string(head[:tracedBodyLimit]) + "...(truncated)"
+	}
+	return text, struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), body), body}
+}
+
+func newCorrelationID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
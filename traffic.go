@@ -0,0 +1,300 @@
+/*
+ * Copyright The Microcks Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package microcks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxExchanges represents the default number of exchanges kept by a TrafficCapture.
+const DefaultMaxExchanges = 100
+
+// Exchange represents a request/response exchange served by Microcks mocks.
+type Exchange struct {
+	// Time is the time the request was received.
+	Time time.Time
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the path of the request, e.g. "/rest/API Pastries/0.0.1/pastries".
+	Path string
+	// Query is the raw query of the request.
+	Query string
+	// RequestHeaders are the headers of the request.
+	RequestHeaders http.Header
+	// RequestBody is the body of the request.
+	RequestBody []byte
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// ResponseHeaders are the headers of the response.
+	ResponseHeaders http.Header
+	// ResponseBody is the body of the response.
+	ResponseBody []byte
+	// Duration is the time Microcks took to serve the response, including the proxy overhead.
+	Duration time.Duration
+}
+
+// TrafficCapture represents a local HTTP proxy in front of Microcks mocks, recording the most recent exchanges.
+type TrafficCapture struct {
+	container *MicrocksContainer
+	server    *http.Server
+	listener  net.Listener
+	target    *url.URL
+
+	maxExchanges  int
+	slowThreshold time.Duration
+	logf          func(format string, args ...any)
+	mutex         sync.Mutex
+	exchanges     []Exchange
+}
+
+// StartTrafficCapture starts a local HTTP proxy in front of Microcks mocks that records the maxExchanges most recent
+// exchanges, DefaultMaxExchanges when maxExchanges is not positive. Routing the application under test through the
+// proxy allows to understand why it got an unexpected mock answer. Exchanges slower than the threshold set by
+// WithSlowRequestThreshold are logged.
+func (container *MicrocksContainer) StartTrafficCapture(ctx context.Context, maxExchanges int) (*TrafficCapture, error) {
+	endpoint, err := container.HttpEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Microcks endpoint: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("error starting traffic capture: %w", err)
+	}
+
+	if maxExchanges <= 0 {
+		maxExchanges = DefaultMaxExchanges
+	}
+	capture := &TrafficCapture{
+		container:     container,
+		listener:      listener,
+		target:        target,
+		maxExchanges:  maxExchanges,
+		slowThreshold: container.slowRequestThreshold,
+		logf:          container.logf,
+	}
+
+	reverseProxy := httputil.NewSingleHostReverseProxy(target)
+	reverseProxy.Transport = container.transport()
+	reverseProxy.ModifyResponse = capture.record
+
+	capture.server = &http.Server{Handler: captureHandler(reverseProxy)}
+	go func() {
+		_ = capture.server.Serve(listener)
+	}()
+
+	return capture, nil
+}
+
+// URL returns the base URL of the proxy.
+func (capture *TrafficCapture) URL() string {
+	return "http://" + capture.listener.Addr().String()
+}
+
+// Endpoint rewrites a Microcks mock endpoint, as returned by RestMockEndpoint or others, so that it goes
+// through the proxy.
+func (capture *TrafficCapture) Endpoint(mockEndpoint string) string {
+	return strings.Replace(mockEndpoint, capture.target.Scheme+"://"+capture.target.Host, capture.URL(), 1)
+}
+
+// Exchanges returns the recorded exchanges matching given method and path prefix, most recent first.
+// An empty method matches any method, an empty path prefix any path.
+func (capture *TrafficCapture) Exchanges(method, pathPrefix string) []Exchange {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	var exchanges []Exchange
+	for i := len(capture.exchanges) - 1; i >= 0; i-- {
+		exchange := capture.exchanges[i]
+		if method != "" && !strings.EqualFold(method, exchange.Method) {
+			continue
+		}
+		if !strings.HasPrefix(exchange.Path, pathPrefix) {
+			continue
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges
+}
+
+// SlowExchanges returns the recorded exchanges that took threshold or more, most recent first. A zero threshold
+// stands for the one set by WithSlowRequestThreshold.
+func (capture *TrafficCapture) SlowExchanges(threshold time.Duration) []Exchange {
+	if threshold == 0 {
+		threshold = capture.slowThreshold
+	}
+
+	var exchanges []Exchange
+	for _, exchange := range capture.Exchanges("", "") {
+		if exchange.Duration >= threshold {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+	return exchanges
+}
+
+// ReceivedRequests returns the requests received by the mock endpoints of given Service through the proxy, in
+// order of arrival, including their headers and payload.
+func (capture *TrafficCapture) ReceivedRequests(serviceName, serviceVersion string) []MockRequest {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	var requests []MockRequest
+	for _, exchange := range capture.exchanges {
+		if !isServiceMockPath(exchange.Path, serviceName, serviceVersion) {
+			continue
+		}
+		uri := exchange.Path
+		if exchange.Query != "" {
+			uri += "?" + exchange.Query
+		}
+		requests = append(requests, MockRequest{
+			ServiceName:    serviceName,
+			ServiceVersion: serviceVersion,
+			Method:         exchange.Method,
+			URI:            uri,
+			Headers:        exchange.RequestHeaders,
+			Body:           exchange.RequestBody,
+		})
+	}
+	return requests
+}
+
+// OperationStatusCodes gets the response status codes of the requests received through the proxy by each REST
+// operation of given Service, e.g. {"GET /pastries/{name}": {200: 2, 404: 1}, "DELETE /pastries/{name}": {}}. Every
+// operation of the Service is listed, requests not matching any being ignored.
+func (capture *TrafficCapture) OperationStatusCodes(ctx context.Context, serviceName, serviceVersion string) (map[string]map[int]int, error) {
+	c, err := capture.container.APIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := c.FindService(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	statusCodes := make(map[string]map[int]int, len(service.Operations))
+	for _, operation := range service.Operations {
+		statusCodes[operation.Name] = make(map[int]int)
+	}
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+	for _, exchange := range capture.exchanges {
+		if !isServiceMockPath(exchange.Path, serviceName, serviceVersion) {
+			continue
+		}
+		if name, ok := matchOperation(service, exchange.Method, exchange.Path); ok {
+			statusCodes[name][exchange.StatusCode]++
+		}
+	}
+	return statusCodes, nil
+}
+
+// isServiceMockPath tells if path is served by a mock endpoint of given Service, e.g. "/rest/API Pastries/0.0.1/pastries".
+func isServiceMockPath(path, serviceName, serviceVersion string) bool {
+	for _, kind := range []string{"rest", "rest-valid", "soap", "graphql"} {
+		prefix := "/" + kind + "/" + serviceName + "/" + serviceVersion
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset forgets all recorded exchanges.
+func (capture *TrafficCapture) Reset() {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	capture.exchanges = nil
+}
+
+// Close stops the proxy.
+func (capture *TrafficCapture) Close(ctx context.Context) error {
+	return capture.server.Shutdown(ctx)
+}
+
+// exchangeContextKey represents the context key holding the Exchange being captured.
+type exchangeContextKey struct{}
+
+// captureHandler wraps next handler, recording the request part of the exchange.
+func captureHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		exchange := &Exchange{
+			Time:           time.Now(),
+			Method:         r.Method,
+			Path:           r.URL.Path,
+			Query:          r.URL.RawQuery,
+			RequestHeaders: r.Header.Clone(),
+			RequestBody:    body,
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), exchangeContextKey{}, exchange)))
+	})
+}
+
+// record completes the exchange of response request with the response part and stores it.
+func (capture *TrafficCapture) record(response *http.Response) error {
+	exchange, ok := response.Request.Context().Value(exchangeContextKey{}).(*Exchange)
+	if !ok {
+		return nil
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("error reading mock response: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange.StatusCode = response.StatusCode
+	exchange.ResponseHeaders = response.Header.Clone()
+	exchange.ResponseBody = body
+	exchange.Duration = time.Since(exchange.Time)
+
+	if capture.slowThreshold > 0 && exchange.Duration >= capture.slowThreshold && capture.logf != nil {
+		capture.logf("Slow mock request %s %s took %s, above threshold of %s", exchange.Method, exchange.Path, exchange.Duration.Round(time.Millisecond), capture.slowThreshold)
+	}
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	capture.exchanges = append(capture.exchanges, *exchange)
+	if len(capture.exchanges) > capture.maxExchanges {
+		capture.exchanges = capture.exchanges[len(capture.exchanges)-capture.maxExchanges:]
+	}
+	return nil
+}